@@ -0,0 +1,294 @@
+package fb
+
+/*
+#include <ibase.h>
+#include <stdlib.h>
+#include <string.h>
+#include "fb.h"
+*/
+import "C"
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+	"unsafe"
+)
+
+func (cursor *Cursor) arrayDesc(sqlvar *C.XSQLVAR) (desc *C.ISC_ARRAY_DESC, err error) {
+	var isc_status [20]C.ISC_STATUS
+
+	relname := C.GoStringN((*C.char)(unsafe.Pointer(&sqlvar.relname[0])), C.int(sqlvar.relname_length))
+	sqlname := C.GoStringN((*C.char)(unsafe.Pointer(&sqlvar.sqlname[0])), C.int(sqlvar.sqlname_length))
+	if relname == "" || sqlname == "" {
+		return nil, errors.New("array column has no relation or field name")
+	}
+	key := relname + "." + sqlname
+	if d, ok := cursor.arrayDescs[key]; ok {
+		return d, nil
+	}
+	crelname := C.CString(relname)
+	defer C.free(unsafe.Pointer(crelname))
+	csqlname := C.CString(sqlname)
+	defer C.free(unsafe.Pointer(csqlname))
+	desc = new(C.ISC_ARRAY_DESC)
+	C.isc_array_lookup_bounds(&isc_status[0], &cursor.connection.db, cursor.transactHandle(),
+		(*C.ISC_SCHAR)(unsafe.Pointer(crelname)), (*C.ISC_SCHAR)(unsafe.Pointer(csqlname)), desc)
+	if err = fbErrorCheck(&isc_status); err != nil {
+		return nil, err
+	}
+	if cursor.arrayDescs == nil {
+		cursor.arrayDescs = make(map[string]*C.ISC_ARRAY_DESC)
+	}
+	cursor.arrayDescs[key] = desc
+	return
+}
+
+func arrayDims(desc *C.ISC_ARRAY_DESC) (dims []int, count int) {
+	count = 1
+	for i := 0; i < int(desc.array_desc_dimensions); i++ {
+		bound := desc.array_desc_bounds[i]
+		n := int(bound.array_bound_upper) - int(bound.array_bound_lower) + 1
+		dims = append(dims, n)
+		count *= n
+	}
+	return
+}
+
+func arrayElementSize(desc *C.ISC_ARRAY_DESC) int {
+	if desc.array_desc_dtype == C.blr_varying {
+		return int(desc.array_desc_length) + C.SHORT_SIZE
+	}
+	return int(desc.array_desc_length)
+}
+
+func (cursor *Cursor) arrayValue(sqlvar *C.XSQLVAR) (val interface{}, err error) {
+	var isc_status [20]C.ISC_STATUS
+
+	var desc *C.ISC_ARRAY_DESC
+	if desc, err = cursor.arrayDesc(sqlvar); err != nil {
+		return
+	}
+	dims, count := arrayDims(desc)
+	size := arrayElementSize(desc)
+	buf := make([]byte, count*size)
+	sliceLength := C.ISC_LONG(len(buf))
+	arrayId := *(*C.ISC_QUAD)(unsafe.Pointer(sqlvar.sqldata))
+	C.isc_array_get_slice(&isc_status[0], &cursor.connection.db, cursor.transactHandle(),
+		&arrayId, desc, unsafe.Pointer(&buf[0]), &sliceLength)
+	if err = fbErrorCheck(&isc_status); err != nil {
+		return
+	}
+	flat := make([]interface{}, count)
+	for i := range flat {
+		if flat[i], err = cursor.arrayElement(desc, buf[i*size:(i+1)*size]); err != nil {
+			return
+		}
+	}
+	return nestArray(flat, dims, reflect.TypeOf(flat[0])).Interface(), nil
+}
+
+func (cursor *Cursor) arrayElement(desc *C.ISC_ARRAY_DESC, b []byte) (val interface{}, err error) {
+	p := unsafe.Pointer(&b[0])
+	scale := C.ISC_SHORT(desc.array_desc_scale)
+	loc := cursor.connection.Location
+	switch desc.array_desc_dtype {
+	case C.blr_text:
+		val = string(b)
+	case C.blr_varying, C.blr_cstring:
+		if i := bytes.IndexByte(b, 0); i >= 0 {
+			b = b[:i]
+		}
+		val = string(b)
+	case C.blr_short:
+		if scale < 0 {
+			val = cursor.scaledValue(int64(*(*C.short)(p)), scale)
+		} else {
+			val = int16(*(*C.short)(p))
+		}
+	case C.blr_long:
+		if scale < 0 {
+			val = cursor.scaledValue(int64(*(*C.ISC_LONG)(p)), scale)
+		} else {
+			val = int32(*(*C.ISC_LONG)(p))
+		}
+	case C.blr_int64:
+		if scale < 0 {
+			val = cursor.scaledValue(int64(*(*C.ISC_INT64)(p)), scale)
+		} else {
+			val = int64(*(*C.ISC_INT64)(p))
+		}
+	case C.blr_float:
+		val = *(*float32)(p)
+	case C.blr_double, C.blr_d_float:
+		val = *(*float64)(p)
+	case C.blr_timestamp:
+		val = timeFromTimestamp(*(*C.ISC_TIMESTAMP)(p), loc)
+	case C.blr_sql_date:
+		val = timeFromIscDate(*(*C.ISC_DATE)(p), loc)
+	case C.blr_sql_time:
+		val = timeFromIscTime(*(*C.ISC_TIME)(p), loc)
+	case C.blr_bool:
+		val = *(*C.uchar)(p) != 0
+	default:
+		err = fmt.Errorf("unsupported array element type %d", desc.array_desc_dtype)
+	}
+	return
+}
+
+func (cursor *Cursor) setArrayElement(desc *C.ISC_ARRAY_DESC, b []byte, v interface{}) (err error) {
+	p := unsafe.Pointer(&b[0])
+	scale := int(-desc.array_desc_scale)
+	loc := cursor.connection.Location
+	switch desc.array_desc_dtype {
+	case C.blr_text, C.blr_varying, C.blr_cstring:
+		var s string
+		if s, err = stringFromIf(v); err != nil {
+			return
+		}
+		limit := int(desc.array_desc_length)
+		if len(s) > limit {
+			return fmt.Errorf("array element overflow: %d bytes exceeds %d byte(s) allowed.", len(s), limit)
+		}
+		fill := byte(0)
+		if desc.array_desc_dtype == C.blr_text {
+			fill = ' '
+		}
+		n := copy(b, s)
+		for i := n; i < len(b); i++ {
+			b[i] = fill
+		}
+	case C.blr_short, C.blr_long, C.blr_int64:
+		var i int64
+		if scale > 0 {
+			i, err = scaledInt64FromIf(v, scale)
+		} else {
+			i, err = int64FromIf(v)
+		}
+		if err != nil {
+			return
+		}
+		switch desc.array_desc_dtype {
+		case C.blr_short:
+			if i < int16min || i > int16max {
+				return errors.New("short integer overflow")
+			}
+			*(*C.short)(p) = C.short(i)
+		case C.blr_long:
+			if i < int32min || i > int32max {
+				return errors.New("integer overflow")
+			}
+			*(*C.ISC_LONG)(p) = C.ISC_LONG(i)
+		default:
+			*(*C.ISC_INT64)(p) = C.ISC_INT64(i)
+		}
+	case C.blr_float:
+		var f float32
+		if f, err = float32FromIf(v); err != nil {
+			return
+		}
+		*(*float32)(p) = f
+	case C.blr_double, C.blr_d_float:
+		var f float64
+		if f, err = float64FromIf(v); err != nil {
+			return
+		}
+		*(*float64)(p) = f
+	case C.blr_timestamp, C.blr_sql_date, C.blr_sql_time:
+		var t time.Time
+		if t, err = timeFromIf(v, loc); err != nil {
+			return
+		}
+		switch desc.array_desc_dtype {
+		case C.blr_timestamp:
+			*(*C.ISC_TIMESTAMP)(p) = timestampFromTime(t, loc)
+		case C.blr_sql_date:
+			*(*C.ISC_DATE)(p) = timestampFromTime(t, loc).timestamp_date
+		default:
+			*(*C.ISC_TIME)(p) = iscTimeFromTime(t, loc)
+		}
+	case C.blr_bool:
+		var bv bool
+		if bv, err = boolFromIf(v); err != nil {
+			return
+		}
+		if bv {
+			*(*C.uchar)(p) = 1
+		} else {
+			*(*C.uchar)(p) = 0
+		}
+	default:
+		err = fmt.Errorf("unsupported array element type %d", desc.array_desc_dtype)
+	}
+	return
+}
+
+func (cursor *Cursor) writeArray(ivar *C.XSQLVAR, v interface{}) (arrayId C.ISC_QUAD, err error) {
+	var isc_status [20]C.ISC_STATUS
+
+	var desc *C.ISC_ARRAY_DESC
+	if desc, err = cursor.arrayDesc(ivar); err != nil {
+		return
+	}
+	dims, count := arrayDims(desc)
+	var flat []interface{}
+	if flat, err = flattenArray(reflect.ValueOf(v), dims, make([]interface{}, 0, count)); err != nil {
+		return
+	}
+	size := arrayElementSize(desc)
+	buf := make([]byte, count*size)
+	for i, elem := range flat {
+		if err = cursor.setArrayElement(desc, buf[i*size:(i+1)*size], elem); err != nil {
+			return
+		}
+	}
+	sliceLength := C.ISC_LONG(len(buf))
+	C.isc_array_put_slice(&isc_status[0], &cursor.connection.db, cursor.transactHandle(),
+		&arrayId, desc, unsafe.Pointer(&buf[0]), &sliceLength)
+	err = fbErrorCheck(&isc_status)
+	return
+}
+
+// nestArray arranges the row-major elements of a slice into nested Go
+// slices, one level per declared dimension.
+func nestArray(flat []interface{}, dims []int, elem reflect.Type) reflect.Value {
+	t := elem
+	for range dims {
+		t = reflect.SliceOf(t)
+	}
+	v := reflect.MakeSlice(t, dims[0], dims[0])
+	step := len(flat) / dims[0]
+	for i := 0; i < dims[0]; i++ {
+		if len(dims) == 1 {
+			v.Index(i).Set(reflect.ValueOf(flat[i]))
+		} else {
+			v.Index(i).Set(nestArray(flat[i*step:(i+1)*step], dims[1:], elem))
+		}
+	}
+	return v
+}
+
+func flattenArray(v reflect.Value, dims []int, flat []interface{}) ([]interface{}, error) {
+	if v.Kind() == reflect.Interface || v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return nil, errors.New("array value expected")
+	}
+	if v.Len() != dims[0] {
+		return nil, fmt.Errorf("array dimension expects %d elements, %d given", dims[0], v.Len())
+	}
+	for i := 0; i < v.Len(); i++ {
+		if len(dims) == 1 {
+			flat = append(flat, v.Index(i).Interface())
+			continue
+		}
+		var err error
+		if flat, err = flattenArray(v.Index(i), dims[1:], flat); err != nil {
+			return nil, err
+		}
+	}
+	return flat, nil
+}
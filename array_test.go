@@ -0,0 +1,35 @@
+package fb
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNestArray(t *testing.T) {
+	flat := []interface{}{int32(1), int32(2), int32(3), int32(4), int32(5), int32(6)}
+
+	one := nestArray(flat, []int{6}, reflect.TypeOf(int32(0))).Interface()
+	if !reflect.DeepEqual(one, []int32{1, 2, 3, 4, 5, 6}) {
+		t.Errorf("unexpected one dimensional array %v", one)
+	}
+	two := nestArray(flat, []int{2, 3}, reflect.TypeOf(int32(0))).Interface()
+	if !reflect.DeepEqual(two, [][]int32{{1, 2, 3}, {4, 5, 6}}) {
+		t.Errorf("unexpected two dimensional array %v", two)
+	}
+}
+
+func TestFlattenArray(t *testing.T) {
+	flat, err := flattenArray(reflect.ValueOf([][]int{{1, 2, 3}, {4, 5, 6}}), []int{2, 3}, nil)
+	if err != nil {
+		t.Fatalf("flattenArray failed: %v", err)
+	}
+	if !reflect.DeepEqual(flat, []interface{}{1, 2, 3, 4, 5, 6}) {
+		t.Errorf("unexpected flattened array %v", flat)
+	}
+	if _, err = flattenArray(reflect.ValueOf([]int{1, 2}), []int{3}, nil); err == nil {
+		t.Errorf("expected error for wrong dimension length")
+	}
+	if _, err = flattenArray(reflect.ValueOf(42), []int{1}, nil); err == nil {
+		t.Errorf("expected error for non-slice value")
+	}
+}
@@ -0,0 +1,210 @@
+package fb
+
+/*
+#include <ibase.h>
+#include "fb.h"
+*/
+import "C"
+
+import (
+	"errors"
+	"io"
+	"unsafe"
+)
+
+const blobSegmentSize = 0x8000
+
+type BlobReader struct {
+	connection *Connection
+	transact   *C.isc_tr_handle
+	id         C.ISC_QUAD
+	handle     C.isc_blob_handle
+	pos        int64
+	length     int64
+	stream     bool
+}
+
+func newBlobReader(conn *Connection, transact *C.isc_tr_handle, id C.ISC_QUAD) *BlobReader {
+	return &BlobReader{connection: conn, transact: transact, id: id}
+}
+
+var blobItemsInfo = [...]C.ISC_SCHAR{
+	C.isc_info_blob_total_length,
+	C.isc_info_blob_type,
+}
+
+func (br *BlobReader) open() (err error) {
+	var isc_status [20]C.ISC_STATUS
+
+	if br.handle != 0 {
+		return
+	}
+	if err = br.connection.check(); err != nil {
+		return
+	}
+	C.isc_open_blob2(&isc_status[0], &br.connection.db, br.transact, &br.handle, &br.id, 0, (*C.ISC_UCHAR)(nil))
+	if err = fbErrorCheck(&isc_status); err != nil {
+		return
+	}
+	br.pos = 0
+
+	var blobInfo [32]C.ISC_SCHAR
+	C.isc_blob_info(
+		&isc_status[0], &br.handle,
+		C.short(unsafe.Sizeof(blobItemsInfo)), &blobItemsInfo[0],
+		C.short(unsafe.Sizeof(blobInfo)), &blobInfo[0])
+	if err = fbErrorCheck(&isc_status); err != nil {
+		return
+	}
+	var length C.short
+	for i := 0; blobInfo[i] != C.isc_info_end; i += int(length) {
+		item := blobInfo[i]
+		i += 1
+		length = C.short(C.isc_vax_integer(&blobInfo[i], 2))
+		i += 2
+		switch item {
+		case C.isc_info_blob_total_length:
+			br.length = int64(C.isc_vax_integer(&blobInfo[i], length))
+		case C.isc_info_blob_type:
+			br.stream = C.isc_vax_integer(&blobInfo[i], length) == C.isc_bpb_type_stream
+		}
+	}
+	return
+}
+
+func (br *BlobReader) Close() (err error) {
+	var isc_status [20]C.ISC_STATUS
+
+	if br.handle == 0 {
+		return
+	}
+	C.isc_close_blob(&isc_status[0], &br.handle)
+	br.handle = 0
+	return fbErrorCheck(&isc_status)
+}
+
+func (br *BlobReader) Length() (int64, error) {
+	if err := br.open(); err != nil {
+		return 0, err
+	}
+	return br.length, nil
+}
+
+func (br *BlobReader) Read(p []byte) (n int, err error) {
+	var isc_status [20]C.ISC_STATUS
+
+	if err = br.open(); err != nil {
+		return
+	}
+	if len(p) == 0 {
+		return
+	}
+	size := len(p)
+	if size > 0xFFFF {
+		size = 0xFFFF
+	}
+	var actualSegLen C.ushort
+	C.isc_get_segment(
+		&isc_status[0], &br.handle, &actualSegLen,
+		C.ushort(size), (*C.ISC_SCHAR)(unsafe.Pointer(&p[0])))
+	n = int(actualSegLen)
+	br.pos += int64(n)
+	switch isc_status[1] {
+	case C.isc_segstr_eof:
+		if n == 0 {
+			err = io.EOF
+		}
+		return
+	case C.isc_segment:
+		// segment was larger than p; the rest comes with the next call
+		return
+	}
+	err = fbErrorCheck(&isc_status)
+	return
+}
+
+func (br *BlobReader) Seek(offset int64, whence int) (pos int64, err error) {
+	var isc_status [20]C.ISC_STATUS
+
+	if err = br.open(); err != nil {
+		return
+	}
+	switch whence {
+	case io.SeekStart:
+		pos = offset
+	case io.SeekCurrent:
+		pos = br.pos + offset
+	case io.SeekEnd:
+		pos = br.length + offset
+	default:
+		return br.pos, errors.New("fb: invalid whence")
+	}
+	if pos < 0 {
+		return br.pos, errors.New("fb: negative blob position")
+	}
+	if br.stream {
+		var result C.ISC_LONG
+		C.isc_seek_blob(&isc_status[0], &br.handle, C.blb_seek_from_head, C.ISC_LONG(pos), &result)
+		if err = fbErrorCheck(&isc_status); err != nil {
+			return br.pos, err
+		}
+		br.pos = int64(result)
+		return br.pos, nil
+	}
+	// segmented blobs can only be read forward, so reopen to go back
+	if pos < br.pos {
+		if err = br.Close(); err != nil {
+			return
+		}
+		if err = br.open(); err != nil {
+			return
+		}
+	}
+	if _, err = io.CopyN(io.Discard, br, pos-br.pos); err == io.EOF {
+		err = nil
+	}
+	return br.pos, err
+}
+
+func (br *BlobReader) readAll() (b []byte, err error) {
+	if err = br.open(); err != nil {
+		return
+	}
+	defer br.Close()
+	b = make([]byte, br.length)
+	_, err = io.ReadFull(br, b)
+	return
+}
+
+func writeBlob(conn *Connection, transact *C.isc_tr_handle, r io.Reader) (blobId C.ISC_QUAD, err error) {
+	var blobHandle C.isc_blob_handle = 0
+	var isc_status [20]C.ISC_STATUS
+
+	C.isc_create_blob2(
+		&isc_status[0], &conn.db, transact,
+		&blobHandle, &blobId, 0, (*C.ISC_SCHAR)(nil))
+	if err = fbErrorCheck(&isc_status); err != nil {
+		return
+	}
+	buf := make([]byte, blobSegmentSize)
+	for {
+		var n int
+		n, err = r.Read(buf)
+		if n > 0 {
+			C.isc_put_segment(&isc_status[0], &blobHandle, C.ushort(n), (*C.ISC_SCHAR)(unsafe.Pointer(&buf[0])))
+			if perr := fbErrorCheck(&isc_status); perr != nil {
+				err = perr
+			}
+		}
+		if err != nil {
+			break
+		}
+	}
+	if err != io.EOF {
+		C.isc_cancel_blob(&isc_status[0], &blobHandle)
+		return
+	}
+	C.isc_close_blob(&isc_status[0], &blobHandle)
+	err = fbErrorCheck(&isc_status)
+	return
+}
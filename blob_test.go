@@ -0,0 +1,87 @@
+package fb
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestBlobStreamRoundTrip(t *testing.T) {
+	st := SuperTest{t}
+	os.Remove(TestFilename)
+
+	conn, err := Create(TestConnectionString)
+	if err != nil {
+		t.Fatalf("Unexpected error creating database: %s", err)
+	}
+	defer conn.Drop()
+
+	if _, err = conn.Execute("CREATE TABLE TEST (ID INT, DATA BLOB)"); err != nil {
+		t.Fatalf("Unexpected error creating table: %s", err)
+	}
+	content := strings.Repeat("0123456789", 100000)
+	if _, err = conn.Execute("INSERT INTO TEST (ID, DATA) VALUES (?, ?)", 1, strings.NewReader(content)); err != nil {
+		t.Fatalf("Unexpected error inserting blob from reader: %s", err)
+	}
+
+	cursor, err := conn.Execute("SELECT DATA FROM TEST WHERE ID = 1")
+	if err != nil {
+		t.Fatalf("Unexpected error selecting blob: %s", err)
+	}
+	defer cursor.Close()
+	cursor.StreamBlobs = true
+	if !cursor.Next() {
+		t.Fatalf("Unexpected error fetching blob: %s", cursor.Err())
+	}
+	br, ok := cursor.Row()[0].(*BlobReader)
+	if !ok {
+		t.Fatalf("Expected *BlobReader, got %T", cursor.Row()[0])
+	}
+	defer br.Close()
+	length, err := br.Length()
+	if err != nil {
+		t.Fatalf("Unexpected error getting blob length: %s", err)
+	}
+	st.Equal(int64(len(content)), length)
+
+	var buf bytes.Buffer
+	if _, err = io.Copy(&buf, br); err != nil {
+		t.Fatalf("Unexpected error reading blob: %s", err)
+	}
+	st.Equal(content, buf.String())
+
+	if _, err = br.Seek(12345, io.SeekStart); err != nil {
+		t.Fatalf("Unexpected error seeking blob: %s", err)
+	}
+	part := make([]byte, 10)
+	if _, err = io.ReadFull(br, part); err != nil {
+		t.Fatalf("Unexpected error reading after seek: %s", err)
+	}
+	st.Equal(content[12345:12355], string(part))
+}
+
+func TestBlobBytesStillMaterialized(t *testing.T) {
+	os.Remove(TestFilename)
+
+	conn, err := Create(TestConnectionString)
+	if err != nil {
+		t.Fatalf("Unexpected error creating database: %s", err)
+	}
+	defer conn.Drop()
+
+	if _, err = conn.Execute("CREATE TABLE TEST (ID INT, DATA BLOB)"); err != nil {
+		t.Fatalf("Unexpected error creating table: %s", err)
+	}
+	if _, err = conn.Execute("INSERT INTO TEST (ID, DATA) VALUES (?, ?)", 1, []byte("BINARY BLOB CONTENTS")); err != nil {
+		t.Fatalf("Unexpected error inserting blob: %s", err)
+	}
+	row, err := conn.QueryRow("SELECT DATA FROM TEST WHERE ID = 1")
+	if err != nil {
+		t.Fatalf("Unexpected error selecting blob: %s", err)
+	}
+	if string(row[0].([]byte)) != "BINARY BLOB CONTENTS" {
+		t.Errorf("Unexpected blob contents: %v", row[0])
+	}
+}
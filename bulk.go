@@ -0,0 +1,248 @@
+package fb
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+type BulkOptions struct {
+	// BatchSize groups rows into EXECUTE BLOCK statements of that many
+	// inserts; 0 or 1 inserts one row per statement.
+	BatchSize int
+	// CommitEvery commits after at least that many rows; 0 commits on Close.
+	CommitEvery int
+}
+
+// RowError reports a row that could not be inserted. Row counts the calls to
+// Insert from zero.
+type RowError struct {
+	Row int64
+	Err error
+}
+
+func (e *RowError) Error() string {
+	return fmt.Sprintf("row %d: %s", e.Row, e.Err)
+}
+
+// BulkInserter loads rows into a table through statements prepared once, in
+// a transaction of its own. Failed rows are collected rather than ending the
+// load; a batch that fails is retried row by row to find them.
+type BulkInserter struct {
+	conn        *Connection
+	tx          *Transaction
+	opts        BulkOptions
+	columns     int
+	single      *Statement
+	block       *Statement
+	pending     []interface{}
+	pendingRows []int64
+	rows        int64
+	committed   int64
+	uncommitted int64
+	errors      []*RowError
+}
+
+func (conn *Connection) NewBulkInserter(table string, columns []string, opts *BulkOptions) (b *BulkInserter, err error) {
+	if len(columns) == 0 {
+		return nil, errors.New("bulk insert needs at least one column")
+	}
+	b = &BulkInserter{conn: conn, columns: len(columns)}
+	if opts != nil {
+		b.opts = *opts
+	}
+	if b.opts.BatchSize < 0 || b.opts.CommitEvery < 0 {
+		return nil, errors.New("negative bulk insert option")
+	}
+	insert := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", table, strings.Join(columns, ", "),
+		strings.TrimSuffix(strings.Repeat("?, ", len(columns)), ", "))
+	if b.single, err = conn.Prepare(insert); err != nil {
+		return nil, err
+	}
+	if b.opts.BatchSize > 1 {
+		if b.block, err = conn.Prepare(bulkBlock(table, columns, b.opts.BatchSize)); err != nil {
+			b.single.Close()
+			return nil, err
+		}
+	}
+	if err = b.begin(); err != nil {
+		b.closeStatements()
+		return nil, err
+	}
+	return b, nil
+}
+
+func bulkBlock(table string, columns []string, rows int) string {
+	var sql bytes.Buffer
+	sql.WriteString("EXECUTE BLOCK (")
+	for r := 0; r < rows; r++ {
+		for c, column := range columns {
+			if r > 0 || c > 0 {
+				sql.WriteString(", ")
+			}
+			fmt.Fprintf(&sql, "P%d_%d TYPE OF COLUMN %s.%s = ?", r, c, table, column)
+		}
+	}
+	sql.WriteString(") AS BEGIN\n")
+	for r := 0; r < rows; r++ {
+		fmt.Fprintf(&sql, "INSERT INTO %s (%s) VALUES (", table, strings.Join(columns, ", "))
+		for c := range columns {
+			if c > 0 {
+				sql.WriteString(", ")
+			}
+			fmt.Fprintf(&sql, ":P%d_%d", r, c)
+		}
+		sql.WriteString(");\n")
+	}
+	sql.WriteString("END")
+	return sql.String()
+}
+
+func (b *BulkInserter) begin() (err error) {
+	if b.tx, err = b.conn.Begin(""); err != nil {
+		return
+	}
+	b.single.cursor.transaction = b.tx
+	if b.block != nil {
+		b.block.cursor.transaction = b.tx
+	}
+	return
+}
+
+// Insert queues a row; it only fails for a wrong number of values or when a
+// commit fails. Errors in the row itself are reported by Errors.
+func (b *BulkInserter) Insert(values ...interface{}) error {
+	if b.tx == nil {
+		return errors.New("bulk inserter is closed")
+	}
+	if len(values) != b.columns {
+		return fmt.Errorf("bulk insert expects %d values, got %d", b.columns, len(values))
+	}
+	row := b.rows
+	b.rows++
+	if b.block == nil {
+		b.insertRow(row, values)
+		return b.commitIfDue()
+	}
+	n := len(b.pending)
+	b.pending = append(b.pending, values...)
+	// a failed batch is retried row by row, so streams are read up front
+	for i, v := range b.pending[n:] {
+		if r, ok := v.(io.Reader); ok {
+			bs, err := io.ReadAll(r)
+			if err != nil {
+				b.pending = b.pending[:n]
+				b.errors = append(b.errors, &RowError{Row: row, Err: err})
+				return nil
+			}
+			b.pending[n+i] = bs
+		}
+	}
+	b.pendingRows = append(b.pendingRows, row)
+	if len(b.pendingRows) < b.opts.BatchSize {
+		return nil
+	}
+	b.flushBatch()
+	return b.commitIfDue()
+}
+
+func (b *BulkInserter) insertRow(row int64, values []interface{}) {
+	if _, err := b.single.Execute(values...); err != nil {
+		b.errors = append(b.errors, &RowError{Row: row, Err: err})
+		return
+	}
+	b.uncommitted++
+}
+
+func (b *BulkInserter) flushBatch() {
+	if len(b.pendingRows) == 0 {
+		return
+	}
+	if b.block != nil && len(b.pendingRows) == b.opts.BatchSize {
+		if _, err := b.block.Execute(b.pending...); err == nil {
+			b.uncommitted += int64(len(b.pendingRows))
+			b.pending, b.pendingRows = b.pending[:0], b.pendingRows[:0]
+			return
+		}
+	}
+	// a short or failed batch goes in row by row
+	for i, row := range b.pendingRows {
+		b.insertRow(row, b.pending[i*b.columns:(i+1)*b.columns])
+	}
+	b.pending, b.pendingRows = b.pending[:0], b.pendingRows[:0]
+}
+
+func (b *BulkInserter) commitIfDue() error {
+	if b.opts.CommitEvery == 0 || b.uncommitted < int64(b.opts.CommitEvery) {
+		return nil
+	}
+	return b.commit(true)
+}
+
+// commit ends the transaction and, when again is set, starts the next one.
+// If the commit fails the rows written since the last commit are rolled back
+// and the error says how many were lost.
+func (b *BulkInserter) commit(again bool) (err error) {
+	tx := b.tx
+	b.tx = nil
+	if err = tx.Commit(); err != nil {
+		tx.Rollback()
+		err = fmt.Errorf("bulk insert commit failed, %d rows rolled back: %w", b.uncommitted, err)
+	} else {
+		b.committed += b.uncommitted
+	}
+	b.uncommitted = 0
+	if again {
+		if berr := b.begin(); err == nil {
+			err = berr
+		}
+	}
+	return
+}
+
+// Flush writes queued rows and commits them.
+func (b *BulkInserter) Flush() error {
+	if b.tx == nil {
+		return errors.New("bulk inserter is closed")
+	}
+	b.flushBatch()
+	return b.commit(true)
+}
+
+// Close flushes and commits the remaining rows and releases the statements.
+func (b *BulkInserter) Close() (err error) {
+	if b.tx != nil {
+		b.flushBatch()
+		err = b.commit(false)
+	}
+	if cerr := b.closeStatements(); err == nil {
+		err = cerr
+	}
+	return
+}
+
+func (b *BulkInserter) closeStatements() (err error) {
+	if b.block != nil {
+		err = b.block.Close()
+		b.block = nil
+	}
+	if b.single != nil {
+		if cerr := b.single.Close(); err == nil {
+			err = cerr
+		}
+		b.single = nil
+	}
+	return
+}
+
+// Written returns the number of rows committed so far; rows lost to a failed
+// commit are not counted.
+func (b *BulkInserter) Written() int64 {
+	return b.committed
+}
+
+func (b *BulkInserter) Errors() []*RowError {
+	return b.errors
+}
@@ -0,0 +1,93 @@
+package fb
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestBulkBlock(t *testing.T) {
+	st := SuperTest{t}
+	st.Equal(`EXECUTE BLOCK (P0_0 TYPE OF COLUMN T.A = ?, P0_1 TYPE OF COLUMN T.B = ?, P1_0 TYPE OF COLUMN T.A = ?, P1_1 TYPE OF COLUMN T.B = ?) AS BEGIN
+INSERT INTO T (A, B) VALUES (:P0_0, :P0_1);
+INSERT INTO T (A, B) VALUES (:P1_0, :P1_1);
+END`, bulkBlock("T", []string{"A", "B"}, 2))
+}
+
+func TestBulkInserter(t *testing.T) {
+	st := SuperTest{t}
+	os.Remove(TestFilename)
+
+	conn, err := Create(TestConnectionString)
+	if err != nil {
+		t.Fatalf("Unexpected error creating database: %s", err)
+	}
+	defer conn.Drop()
+
+	if _, err = conn.Execute("CREATE TABLE TEST (ID INT NOT NULL PRIMARY KEY, NAME VARCHAR(20))"); err != nil {
+		t.Fatalf("Error executing schema: %s", err)
+	}
+
+	// rows committed before Close: none without CommitEvery, otherwise
+	// the commits after rows 29, 59 and 89, with row 42 failing
+	committed := []int64{0, 89}
+	for i, opts := range []*BulkOptions{nil, {BatchSize: 10, CommitEvery: 25}} {
+		if _, err = conn.Execute("DELETE FROM TEST"); err != nil {
+			t.Fatalf("Error clearing table: %s", err)
+		}
+		b, err := conn.NewBulkInserter("TEST", []string{"ID", "NAME"}, opts)
+		if err != nil {
+			t.Fatalf("Unexpected error creating bulk inserter: %s", err)
+		}
+		for i := 0; i < 95; i++ {
+			id := i
+			if i == 42 {
+				id = 41 // duplicate key
+			}
+			st.Nil(b.Insert(id, "name"))
+		}
+		st.True(b.Insert(1) != nil)
+		st.Equal(committed[i], b.Written())
+		st.Nil(b.Close())
+		st.True(b.Insert(1, "x") != nil)
+
+		st.Equal(int64(94), b.Written())
+		st.MustEqual(1, len(b.Errors()))
+		st.Equal(int64(42), b.Errors()[0].Row)
+
+		row, err := conn.QueryRow("SELECT COUNT(*) FROM TEST")
+		st.Nil(err)
+		st.Equal(int32(94), row[0])
+	}
+}
+
+func TestBulkInserterStreams(t *testing.T) {
+	st := SuperTest{t}
+	os.Remove(TestFilename)
+
+	conn, err := Create(TestConnectionString)
+	if err != nil {
+		t.Fatalf("Unexpected error creating database: %s", err)
+	}
+	defer conn.Drop()
+
+	if _, err = conn.Execute("CREATE TABLE TEST (ID INT NOT NULL PRIMARY KEY, DATA BLOB SUB_TYPE TEXT)"); err != nil {
+		t.Fatalf("Error executing schema: %s", err)
+	}
+
+	b, err := conn.NewBulkInserter("TEST", []string{"ID", "DATA"}, &BulkOptions{BatchSize: 3})
+	if err != nil {
+		t.Fatalf("Unexpected error creating bulk inserter: %s", err)
+	}
+	// the duplicate fails the batch, which is then retried row by row
+	st.Nil(b.Insert(1, strings.NewReader("one")))
+	st.Nil(b.Insert(1, strings.NewReader("dup")))
+	st.Nil(b.Insert(2, strings.NewReader("two")))
+	st.Nil(b.Close())
+	st.Equal(int64(2), b.Written())
+	st.Equal(1, len(b.Errors()))
+
+	row, err := conn.QueryRow("SELECT DATA FROM TEST WHERE ID = 2")
+	st.Nil(err)
+	st.Equal("two", row[0])
+}
@@ -19,23 +19,47 @@ ISC_STATUS isc_start_transaction2(ISC_STATUS* isc_status,
 import "C"
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"strings"
+	"sync"
 	"time"
 	"unicode"
 	"unsafe"
 )
 
+var ErrCancelled = errors.New("fb: operation cancelled")
+
 type Connection struct {
-	database     *Database
-	db           C.isc_db_handle
-	transact     C.isc_tr_handle
-	dialect      C.ushort
-	db_dialect   C.ushort
-	dropped      bool
-	rowsAffected int
-	Location     *time.Location
+	database      *Database
+	db            C.isc_db_handle
+	transact      C.isc_tr_handle
+	dialect       C.ushort
+	db_dialect    C.ushort
+	dropped       bool
+	rowsAffected  int
+	Location      *time.Location
+	timeZoneIds   map[string]uint16
+	timeZoneNames map[uint16]string
+	// transactions started with Begin and not yet finished
+	transactions map[*Transaction]struct{}
+}
+
+func (conn *Connection) cancelOperation() error {
+	var isc_status [20]C.ISC_STATUS
+
+	C.fb_cancel_operation(&isc_status[0], &conn.db, C.fb_cancel_raise)
+	return fbErrorCheck(&isc_status)
+}
+
+// clearCancel drops a cancel request still pending on the attachment.
+func (conn *Connection) clearCancel() {
+	var isc_status [20]C.ISC_STATUS
+
+	C.fb_cancel_operation(&isc_status[0], &conn.db, C.fb_cancel_disable)
+	C.fb_cancel_operation(&isc_status[0], &conn.db, C.fb_cancel_enable)
 }
 
 func (conn *Connection) check() error {
@@ -90,19 +114,7 @@ func (conn *Connection) Columns(tableName string) (columns []*Column, err error)
 			&col.Nullable); err != nil {
 			return
 		}
-		col.Name = strings.TrimRightFunc(col.Name, unicode.IsSpace)
-		if conn.database.LowercaseNames && !hasLowercase(col.Name) {
-			col.Name = strings.ToLower(col.Name)
-		}
-		col.Domain = strings.TrimRightFunc(col.Domain, unicode.IsSpace)
-		if strings.HasPrefix(col.Domain, "RDB$") {
-			col.Domain = ""
-		}
-		col.SqlType = sqlTypeFromCode(int(sqlType), int(col.SqlSubtype.Value))
-		if !col.Default.Null {
-			col.Default.Value = strings.Replace(col.Default.Value, "DEFAULT ", "", 1)
-			col.Default.Value = strings.TrimLeftFunc(col.Default.Value, unicode.IsSpace)
-		}
+		conn.fixColumn(&col, sqlType)
 		columns = append(columns, &col)
 	}
 	if cursor.Err() != io.EOF {
@@ -111,6 +123,23 @@ func (conn *Connection) Columns(tableName string) (columns []*Column, err error)
 	return
 }
 
+// fixColumn tidies a column read from the system tables.
+func (conn *Connection) fixColumn(col *Column, sqlType int16) {
+	col.Name = strings.TrimRightFunc(col.Name, unicode.IsSpace)
+	if conn.database.LowercaseNames && !hasLowercase(col.Name) {
+		col.Name = strings.ToLower(col.Name)
+	}
+	col.Domain = strings.TrimRightFunc(col.Domain, unicode.IsSpace)
+	if strings.HasPrefix(col.Domain, "RDB$") {
+		col.Domain = ""
+	}
+	col.SqlType = sqlTypeFromCode(int(sqlType), int(col.SqlSubtype.Value))
+	if !col.Default.Null {
+		col.Default.Value = strings.Replace(col.Default.Value, "DEFAULT ", "", 1)
+		col.Default.Value = strings.TrimLeftFunc(col.Default.Value, unicode.IsSpace)
+	}
+}
+
 func (conn *Connection) Commit() (err error) {
 	var isc_status [20]C.ISC_STATUS
 
@@ -161,17 +190,19 @@ func (conn *Connection) Execute(sql string, args ...interface{}) (cursor *Cursor
 	return
 }
 
-func (conn *Connection) ExecuteScript(sql string) (err error) {
-	// TODO: handle "set term"
-	script := strings.Split(sql, ";")
-	for _, stmt := range script {
-		if strings.TrimSpace(stmt) == "" {
-			continue
-		}
-		_, err = conn.Execute(stmt)
-		if err != nil {
-			return
-		}
+// ExecuteContext is Execute, cancelled when ctx is done. A returned Cursor
+// keeps watching ctx in NextContext until it is closed or exhausted.
+func (conn *Connection) ExecuteContext(ctx context.Context, sql string, args ...interface{}) (cursor *Cursor, err error) {
+	w := conn.watchContext(ctx)
+	if err = w.begin(); err != nil {
+		w.stop()
+		return
+	}
+	cursor, err = conn.Execute(sql, args...)
+	if err = w.end(err); err == nil && cursor != nil {
+		cursor.watch = w
+	} else {
+		w.stop()
 	}
 	return
 }
@@ -293,6 +324,30 @@ func (conn *Connection) QueryRow(sql string, args ...interface{}) (row Row, err
 	return
 }
 
+// ExecReturning executes an INSERT, UPDATE or DELETE with a RETURNING clause
+// and returns the row it produced, or io.EOF when no row matched.
+func (conn *Connection) ExecReturning(sql string, args ...interface{}) (row Row, err error) {
+	var cursor *Cursor
+	if cursor, err = conn.Execute(sql, args...); err != nil {
+		return
+	}
+	if cursor == nil {
+		return nil, &Error{Message: "statement returns no values"}
+	}
+	if cursor.Next() {
+		row = cursor.Row()
+	} else {
+		err = cursor.Err()
+	}
+	if cerr := cursor.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		row = nil
+	}
+	return
+}
+
 func (conn *Connection) QueryRowMap(sql string, args ...interface{}) (row map[string]interface{}, err error) {
 	var cursor *Cursor
 	if cursor, err = conn.Execute(sql, args...); err != nil {
@@ -336,6 +391,21 @@ func (conn *Connection) QueryRows(sql string, args ...interface{}) (rows []Row,
 	return
 }
 
+func (conn *Connection) QueryRowsContext(ctx context.Context, sql string, args ...interface{}) (rows []Row, err error) {
+	var cursor *Cursor
+	if cursor, err = conn.ExecuteContext(ctx, sql, args...); err != nil || cursor == nil {
+		return
+	}
+	defer cursor.Close()
+	for cursor.NextContext(ctx) {
+		rows = append(rows, cursor.Row())
+	}
+	if cursor.Err() != io.EOF {
+		err = cursor.Err()
+	}
+	return
+}
+
 func (conn *Connection) RoleNames() (names []string, err error) {
 	const sql = "SELECT RDB$ROLE_NAME FROM RDB$ROLES WHERE RDB$SYSTEM_FLAG = 0 ORDER BY RDB$ROLE_NAME"
 	return conn.names(sql)
@@ -351,6 +421,20 @@ func (conn *Connection) Rollback() (err error) {
 	return
 }
 
+// rollbackTransactions rolls back the connection's transaction and those
+// started with Begin that are still open.
+func (conn *Connection) rollbackTransactions() (err error) {
+	for tx := range conn.transactions {
+		if rerr := tx.Rollback(); err == nil {
+			err = rerr
+		}
+	}
+	if rerr := conn.Rollback(); err == nil {
+		err = rerr
+	}
+	return
+}
+
 func (conn *Connection) RowsAffected() int {
 	return conn.rowsAffected
 }
@@ -362,23 +446,47 @@ func (conn *Connection) TableNames() (names []string, err error) {
 	return conn.names(sql)
 }
 
-func (conn *Connection) TransactionStart(options string) error {
-	var isc_status [20]C.ISC_STATUS
-
+// TransactionStart accepts either the legacy option string, such as
+// "READ ONLY ISOLATION LEVEL SNAPSHOT", or TransactionOptions.
+func (conn *Connection) TransactionStart(options interface{}) error {
 	if conn.TransactionStarted() {
 		return &Error{Message: "A transaction has been already started"}
 	}
+	return conn.startTransaction(&conn.transact, options)
+}
+
+func (conn *Connection) startTransaction(transact *C.isc_tr_handle, options interface{}) error {
+	var isc_status [20]C.ISC_STATUS
+
 	var tpb *C.char = (*C.char)(nil)
 	var tpb_len C.long = 0
-	if options != "" {
-		options2 := C.CString(options)
-		defer C.free(unsafe.Pointer(options2))
-		tpb = C.trans_parseopts(options2, &tpb_len)
-		if tpb_len < 0 {
-			return &Error{Message: C.GoString(tpb)}
+	switch options := options.(type) {
+	case nil:
+	case string:
+		if options != "" {
+			options2 := C.CString(options)
+			defer C.free(unsafe.Pointer(options2))
+			tpb = C.trans_parseopts(options2, &tpb_len)
+			if tpb_len < 0 {
+				return &Error{Message: C.GoString(tpb)}
+			}
 		}
+	case TransactionOptions:
+		return conn.startTransaction(transact, &options)
+	case *TransactionOptions:
+		if options == nil {
+			break
+		}
+		b, err := options.tpb()
+		if err != nil {
+			return &Error{Message: err.Error()}
+		}
+		tpb = (*C.char)(C.CBytes(b))
+		tpb_len = C.long(len(b))
+	default:
+		return &Error{Message: fmt.Sprintf("unsupported transaction options %T", options)}
 	}
-	C.isc_start_transaction2(&isc_status[0], &conn.transact, 1, &conn.db, tpb_len, tpb)
+	C.isc_start_transaction2(&isc_status[0], transact, 1, &conn.db, tpb_len, tpb)
 	C.free(unsafe.Pointer(tpb))
 	return fbErrorCheck(&isc_status)
 }
@@ -398,3 +506,78 @@ func (conn *Connection) ViewNames() (names []string, err error) {
 		ORDER BY RDB$RELATION_NAME`
 	return conn.names(sql)
 }
+
+// contextWatch interrupts calls to the server on the attachment when ctx is
+// done. It lives as long as a cursor; begin and end bracket each call, and
+// only a call in progress is cancelled.
+type contextWatch struct {
+	conn      *Connection
+	ctx       context.Context
+	mu        sync.Mutex
+	busy      bool
+	cancelled bool
+	done      chan struct{}
+	exited    chan struct{}
+	stopOnce  sync.Once
+}
+
+func (conn *Connection) watchContext(ctx context.Context) *contextWatch {
+	w := &contextWatch{conn: conn, ctx: ctx, done: make(chan struct{}), exited: make(chan struct{})}
+	if ctx.Done() == nil {
+		close(w.exited)
+		return w
+	}
+	go func() {
+		defer close(w.exited)
+		select {
+		case <-ctx.Done():
+			w.mu.Lock()
+			if w.busy {
+				w.conn.cancelOperation()
+				w.cancelled = true
+			}
+			w.mu.Unlock()
+		case <-w.done:
+		}
+	}()
+	return w
+}
+
+func cancelError(ctx context.Context) error {
+	return fmt.Errorf("%w: %w", ErrCancelled, ctx.Err())
+}
+
+// begin marks a call as running; it fails if ctx is already done.
+func (w *contextWatch) begin() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.ctx.Err() != nil {
+		return cancelError(w.ctx)
+	}
+	w.busy = true
+	return nil
+}
+
+// end marks the call as finished and returns its error, replaced by the
+// cancellation error when it was cancelled. A cancel that came in just after
+// the call returned would be held by the server for the next call, so it is
+// cleared.
+func (w *contextWatch) end(err error) error {
+	w.mu.Lock()
+	w.busy = false
+	cancelled := w.cancelled
+	w.cancelled = false
+	w.mu.Unlock()
+	if cancelled {
+		w.conn.clearCancel()
+		if err != nil {
+			err = cancelError(w.ctx)
+		}
+	}
+	return err
+}
+
+func (w *contextWatch) stop() {
+	w.stopOnce.Do(func() { close(w.done) })
+	<-w.exited
+}
@@ -1,11 +1,15 @@
 package fb
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 	"unicode"
 )
 
@@ -713,6 +717,67 @@ func TestUpdateRows(t *testing.T) {
 	st.Equal(testRows, conn.RowsAffected())
 }
 
+func TestExecReturning(t *testing.T) {
+	st := SuperTest{t}
+	os.Remove(TestFilename)
+
+	conn, err := Create(TestConnectionString)
+	if err != nil {
+		t.Fatalf("Unexpected error creating database: %s", err)
+	}
+	defer conn.Drop()
+
+	const sqlSchema = `
+		CREATE TABLE TEST (ID INT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY, NAME VARCHAR(20));`
+	if err = conn.ExecuteScript(sqlSchema); err != nil {
+		t.Fatalf("Error executing schema: %s", err)
+	}
+
+	row, err := conn.ExecReturning("INSERT INTO TEST (NAME) VALUES (?) RETURNING ID", "a")
+	st.Nil(err)
+	st.MustEqual(1, len(row))
+	st.Equal(int32(1), row[0])
+	st.Equal(1, conn.RowsAffected())
+
+	row, err = conn.ExecReturning("UPDATE TEST SET NAME = ? WHERE ID = ? RETURNING OLD.NAME, NEW.NAME", "b", 1)
+	st.Nil(err)
+	st.MustEqual(2, len(row))
+	st.Equal("a", row[0])
+	st.Equal("b", row[1])
+
+	// Execute hands back the returned row through a cursor
+	cursor, err := conn.Execute("INSERT INTO TEST (NAME) VALUES ('c') RETURNING ID, NAME")
+	st.Nil(err)
+	st.MustEqual(true, cursor != nil)
+	st.True(cursor.Next())
+	st.Equal(int32(2), cursor.Row()[0])
+	st.Equal("c", cursor.RowMap()["NAME"])
+	st.False(cursor.Next())
+	st.Equal(io.EOF, cursor.Err())
+	st.Nil(cursor.Close())
+
+	row, err = conn.ExecReturning("UPDATE TEST SET NAME = 'x' WHERE ID = 99 RETURNING ID, NAME")
+	st.Equal(io.EOF, err)
+	st.True(row == nil)
+	cursor, err = conn.Execute("DELETE FROM TEST WHERE ID = 99 RETURNING ID")
+	st.Nil(err)
+	st.MustEqual(true, cursor != nil)
+	st.False(cursor.Next())
+	st.Equal(io.EOF, cursor.Err())
+	st.Nil(cursor.Close())
+
+	row, err = conn.ExecReturning("DELETE FROM TEST WHERE ID = 2 RETURNING NAME")
+	st.Nil(err)
+	st.Equal("c", row[0])
+
+	_, err = conn.ExecReturning("DELETE FROM TEST")
+	st.True(err != nil)
+
+	count, err := conn.QueryRow("SELECT COUNT(*) FROM TEST")
+	st.Nil(err)
+	st.Equal(int32(0), count[0])
+}
+
 func TestNextSequenceValue(t *testing.T) {
 	st := SuperTest{t}
 	os.Remove(TestFilename)
@@ -878,6 +943,352 @@ func TestIndexesLower(t *testing.T) {
 	st.Equal("name", indexes[0].Columns[1])
 }
 
+func TestCancelAfterCompletion(t *testing.T) {
+	os.Remove(TestFilename)
+
+	conn, err := Create(TestConnectionString)
+	if err != nil {
+		t.Fatalf("Unexpected error creating database: %s", err)
+	}
+	defer conn.Drop()
+
+	// the cancel reaches an idle attachment after the call has returned
+	ctx, cancel := context.WithCancel(context.Background())
+	w := conn.watchContext(ctx)
+	if err = w.begin(); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	_, callErr := conn.QueryRow("SELECT 1 FROM RDB$DATABASE")
+	cancel()
+	<-w.exited
+	if err = w.end(callErr); err != nil {
+		t.Fatalf("Completed call should not report cancellation: %s", err)
+	}
+	w.stop()
+
+	if _, err = conn.QueryRow("SELECT 1 FROM RDB$DATABASE"); err != nil {
+		t.Fatalf("Connection should be usable after a late cancel: %s", err)
+	}
+}
+
+func TestNextContextWatch(t *testing.T) {
+	st := SuperTest{t}
+	os.Remove(TestFilename)
+
+	conn, err := Create(TestConnectionString)
+	if err != nil {
+		t.Fatalf("Unexpected error creating database: %s", err)
+	}
+	defer conn.Drop()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cursor, err := conn.ExecuteContext(ctx, "SELECT RDB$RELATION_ID FROM RDB$RELATIONS")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	// one watch serves every fetch and ends with the cursor
+	w := cursor.watch
+	st.True(w != nil)
+	st.True(cursor.NextContext(ctx))
+	st.True(cursor.NextContext(ctx))
+	st.True(cursor.watch == w)
+	cancel()
+	st.False(cursor.NextContext(ctx))
+	st.True(errors.Is(cursor.Err(), context.Canceled))
+	st.True(cursor.watch == nil)
+	st.Nil(cursor.Close())
+}
+
+const sqlLongRunning = `SELECT COUNT(*) FROM RDB$FIELDS A, RDB$FIELDS B, RDB$FIELDS C, RDB$FIELDS D`
+
+func TestExecuteContextCancel(t *testing.T) {
+	os.Remove(TestFilename)
+
+	conn, err := Create(TestConnectionString)
+	if err != nil {
+		t.Fatalf("Unexpected error creating database: %s", err)
+	}
+	defer conn.Drop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, err = conn.QueryRowsContext(ctx, sqlLongRunning)
+	if !errors.Is(err, ErrCancelled) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected ErrCancelled for the deadline, got %v", err)
+	}
+
+	row, err := conn.QueryRow("SELECT 1 FROM RDB$DATABASE")
+	if err != nil {
+		t.Fatalf("Connection should be usable after cancellation: %s", err)
+	}
+	if row[0].(int32) != 1 {
+		t.Errorf("Expected 1, got %v", row[0])
+	}
+}
+
+func TestExecuteContextDone(t *testing.T) {
+	os.Remove(TestFilename)
+
+	conn, err := Create(TestConnectionString)
+	if err != nil {
+		t.Fatalf("Unexpected error creating database: %s", err)
+	}
+	defer conn.Drop()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err = conn.ExecuteContext(ctx, "SELECT 1 FROM RDB$DATABASE"); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected ErrCancelled, got %v", err)
+	}
+	if conn.TransactionStarted() {
+		t.Error("No transaction should be started for a cancelled context.")
+	}
+
+	rows, err := conn.QueryRowsContext(context.Background(), "SELECT 1 FROM RDB$DATABASE")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if len(rows) != 1 {
+		t.Errorf("Expected 1 row, got %d", len(rows))
+	}
+
+	// statements without a result set give no cursor to close
+	ctx, cancel = context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	if rows, err = conn.QueryRowsContext(ctx, "CREATE TABLE TEST (ID INT)"); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if len(rows) != 0 {
+		t.Errorf("Expected no rows, got %d", len(rows))
+	}
+	if _, err = conn.QueryRows("SELECT ID FROM TEST"); err != nil {
+		t.Errorf("Unexpected error after a finished context operation: %s", err)
+	}
+}
+
+func TestConstraints(t *testing.T) {
+	st := SuperTest{t}
+	os.Remove(TestFilename)
+
+	conn, err := Create(TestConnectionString)
+	if err != nil {
+		t.Fatalf("Unexpected error creating database: %s", err)
+	}
+	defer conn.Drop()
+
+	const sqlSchema = `
+		CREATE TABLE PARENT (A INT NOT NULL, B INT NOT NULL, CODE VARCHAR(10), CONSTRAINT PK_PARENT PRIMARY KEY (A, B));
+		CREATE TABLE CHILD (ID INT NOT NULL PRIMARY KEY, PA INT, PB INT, QTY INT,
+			CONSTRAINT FK_CHILD_PARENT FOREIGN KEY (PA, PB) REFERENCES PARENT (A, B) ON DELETE CASCADE,
+			CONSTRAINT UQ_CHILD UNIQUE (PB, PA),
+			CONSTRAINT CK_QTY CHECK (QTY > 0));`
+	if err = conn.ExecuteScript(sqlSchema); err != nil {
+		t.Fatalf("Error executing schema: %s", err)
+	}
+
+	keys, err := conn.ForeignKeys("CHILD")
+	st.Nil(err)
+	st.MustEqual(1, len(keys))
+	st.Equal("FK_CHILD_PARENT", keys[0].Name)
+	st.Equal("CHILD", keys[0].TableName)
+	st.True(reflect.DeepEqual([]string{"PA", "PB"}, keys[0].Columns))
+	st.Equal("PARENT", keys[0].ReferencedTable)
+	st.True(reflect.DeepEqual([]string{"A", "B"}, keys[0].ReferencedColumns))
+	st.Equal("RESTRICT", keys[0].OnUpdate)
+	st.Equal("CASCADE", keys[0].OnDelete)
+
+	keys, err = conn.ForeignKeys("PARENT")
+	st.Nil(err)
+	st.Equal(0, len(keys))
+
+	unique, err := conn.UniqueConstraints("CHILD")
+	st.Nil(err)
+	st.MustEqual(1, len(unique))
+	st.Equal("UQ_CHILD", unique[0].Name)
+	st.True(reflect.DeepEqual([]string{"PB", "PA"}, unique[0].Columns))
+
+	checks, err := conn.CheckConstraints("CHILD")
+	st.Nil(err)
+	st.MustEqual(1, len(checks))
+	st.Equal("CK_QTY", checks[0].Name)
+	st.Equal("CHECK (QTY > 0)", checks[0].Source)
+
+	// names returned with lowercase_names are accepted back
+	conn.database.LowercaseNames = true
+	keys, err = conn.ForeignKeys("child")
+	st.Nil(err)
+	st.MustEqual(1, len(keys))
+	st.Equal("child", keys[0].TableName)
+	st.Equal("parent", keys[0].ReferencedTable)
+	unique, err = conn.UniqueConstraints("child")
+	st.Nil(err)
+	st.Equal(1, len(unique))
+	checks, err = conn.CheckConstraints("child")
+	st.Nil(err)
+	st.Equal(1, len(checks))
+}
+
+func TestProcedure(t *testing.T) {
+	st := SuperTest{t}
+	os.Remove(TestFilename)
+
+	conn, err := Create(TestConnectionString)
+	if err != nil {
+		t.Fatalf("Unexpected error creating database: %s", err)
+	}
+	defer conn.Drop()
+
+	const sqlSchema = `
+		CREATE DOMAIN QUANTITY INTEGER NOT NULL;
+		SET TERM ^ ;
+		CREATE PROCEDURE ADD_ONE (X QUANTITY, LABEL VARCHAR(10) = 'n') RETURNS (Y INTEGER, TXT VARCHAR(20)) AS
+		BEGIN
+			Y = X + 1;
+			TXT = LABEL || Y;
+		END^
+		CREATE PROCEDURE COUNT_TO (N INTEGER) RETURNS (I INTEGER) AS
+		BEGIN
+			I = 0;
+			WHILE (I < N) DO
+			BEGIN
+				I = I + 1;
+				SUSPEND;
+			END
+		END^
+		SET TERM ; ^`
+	if err = conn.ExecuteScript(sqlSchema); err != nil {
+		t.Fatalf("Error executing schema: %s", err)
+	}
+
+	proc, err := conn.Procedure("ADD_ONE")
+	if err != nil {
+		t.Fatalf("Unexpected error reading procedure: %s", err)
+	}
+	st.Equal("ADD_ONE", proc.Name)
+	st.False(proc.Selectable)
+	st.True(strings.Contains(proc.Source, "Y = X + 1;"))
+	st.MustEqual(2, len(proc.Inputs))
+	st.MustEqual(2, len(proc.Outputs))
+	st.Equal("X", proc.Inputs[0].Name)
+	st.Equal(0, proc.Inputs[0].Position)
+	st.Equal("QUANTITY", proc.Inputs[0].Domain)
+	st.Equal("INTEGER", proc.Inputs[0].SqlType)
+	st.False(proc.Inputs[0].Nullable.Value)
+	st.Equal("LABEL", proc.Inputs[1].Name)
+	st.Equal(1, proc.Inputs[1].Position)
+	st.Equal("VARCHAR", proc.Inputs[1].SqlType)
+	st.Equal("'n'", proc.Inputs[1].Default.Value)
+	st.True(proc.Inputs[1].Nullable.Value)
+	st.Equal("Y", proc.Outputs[0].Name)
+	st.Equal("TXT", proc.Outputs[1].Name)
+	st.Equal(int16(20), proc.Outputs[1].Length)
+
+	proc, err = conn.Procedure("COUNT_TO")
+	st.Nil(err)
+	st.True(proc.Selectable)
+	st.Equal(1, len(proc.Inputs))
+	st.Equal(1, len(proc.Outputs))
+
+	_, err = conn.Procedure("MISSING")
+	st.True(err != nil)
+}
+
+func TestCallProcedure(t *testing.T) {
+	st := SuperTest{t}
+	os.Remove(TestFilename)
+
+	conn, err := Create(TestConnectionString)
+	if err != nil {
+		t.Fatalf("Unexpected error creating database: %s", err)
+	}
+	defer conn.Drop()
+
+	const sqlSchema = `
+		SET TERM ^ ;
+		CREATE PROCEDURE ADD_ONE (X INTEGER NOT NULL, LABEL VARCHAR(10) = 'n') RETURNS (Y INTEGER, TXT VARCHAR(20)) AS
+		BEGIN
+			Y = X + 1;
+			TXT = LABEL || Y;
+		END^
+		CREATE PROCEDURE COUNT_TO (N INTEGER) RETURNS (I INTEGER) AS
+		BEGIN
+			I = 0;
+			WHILE (I < N) DO
+			BEGIN
+				I = I + 1;
+				SUSPEND;
+			END
+		END^
+		CREATE PROCEDURE "Mixed Case" RETURNS (Y INTEGER) AS
+		BEGIN
+			Y = 7;
+		END^
+		SET TERM ; ^`
+	if err = conn.ExecuteScript(sqlSchema); err != nil {
+		t.Fatalf("Error executing schema: %s", err)
+	}
+
+	row, _, err := conn.CallProcedure("Mixed Case")
+	st.Nil(err)
+	st.Equal(int32(7), row[0])
+	row, _, err = conn.CallProcedure(`"Mixed Case"`)
+	st.Nil(err)
+	st.Equal(int32(7), row[0])
+	_, _, err = conn.CallProcedure("ADD_ONE (1, 'x'); DELETE FROM RDB$ROLES; --")
+	st.True(err != nil)
+
+	row, cursor, err := conn.CallProcedure("ADD_ONE", 41, "x")
+	st.Nil(err)
+	st.True(cursor == nil)
+	st.MustEqual(2, len(row))
+	st.Equal(int32(42), row[0])
+	st.Equal("x42", row[1])
+
+	row, _, err = conn.CallProcedure("ADD_ONE", 1)
+	st.Nil(err)
+	st.Equal("n2", row[1])
+
+	_, _, err = conn.CallProcedure("ADD_ONE")
+	st.True(err != nil)
+	_, _, err = conn.CallProcedure("ADD_ONE", nil)
+	st.True(err != nil)
+	_, _, err = conn.CallProcedure("ADD_ONE", 1, "x", 2)
+	st.True(err != nil)
+
+	row, cursor, err = conn.CallProcedure("COUNT_TO", 3)
+	st.Nil(err)
+	st.True(row == nil)
+	st.MustEqual(true, cursor != nil)
+	var count int32
+	for cursor.Next() {
+		st.Nil(cursor.Scan(&count))
+	}
+	st.Equal(io.EOF, cursor.Err())
+	st.Equal(int32(3), count)
+	st.Nil(cursor.Close())
+
+	lower, err := Connect(TestConnectionStringLowerNames)
+	if err != nil {
+		t.Fatalf("Unexpected error connecting: %s", err)
+	}
+	defer lower.Close()
+	proc, err := lower.Procedure("add_one")
+	st.Nil(err)
+	st.Equal("add_one", proc.Name)
+	row, _, err = lower.CallProcedure("add_one", 1)
+	st.Nil(err)
+	st.Equal(int32(2), row[0])
+}
+
+func TestQuoteIdentifier(t *testing.T) {
+	st := SuperTest{t}
+	st.Equal("ADD_ONE", quoteIdentifier("ADD_ONE"))
+	st.Equal(`"Mixed Case"`, quoteIdentifier("Mixed Case"))
+	st.Equal(`"A""B"`, quoteIdentifier(`A"B`))
+	st.Equal(`"1X"`, quoteIdentifier("1X"))
+}
+
 // MBA 11.5s go1.1.2
 func BenchmarkInsert1K(b *testing.B) {
 	b.StopTimer()
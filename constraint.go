@@ -0,0 +1,169 @@
+package fb
+
+import (
+	"io"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+type ForeignKey struct {
+	Name              string
+	TableName         string
+	Columns           []string
+	ReferencedTable   string
+	ReferencedColumns []string
+	OnUpdate          string
+	OnDelete          string
+}
+
+type UniqueConstraint struct {
+	Name      string
+	TableName string
+	Columns   []string
+}
+
+type CheckConstraint struct {
+	Name      string
+	TableName string
+	Source    string
+}
+
+func (conn *Connection) metaName(name string) string {
+	name = strings.TrimRightFunc(name, unicode.IsSpace)
+	if conn.database.LowercaseNames && !hasLowercase(name) {
+		name = strings.ToLower(name)
+	}
+	return name
+}
+
+// storedName maps a name as returned by the metadata methods, or a quoted
+// identifier, back to the name kept in the system tables.
+func (conn *Connection) storedName(name string) string {
+	if len(name) > 1 && strings.HasPrefix(name, `"`) && strings.HasSuffix(name, `"`) {
+		return strings.Replace(name[1:len(name)-1], `""`, `"`, -1)
+	}
+	if conn.database.LowercaseNames && strings.ToLower(name) == name {
+		name = strings.ToUpper(name)
+	}
+	return name
+}
+
+var reRegularIdentifier = regexp.MustCompile(`^[A-Z][A-Z0-9_$]*$`)
+
+// quoteIdentifier quotes a stored name unless it can be written as is, which
+// keeps dialect 1 connections working for ordinary names.
+func quoteIdentifier(name string) string {
+	if reRegularIdentifier.MatchString(name) {
+		return name
+	}
+	return `"` + strings.Replace(name, `"`, `""`, -1) + `"`
+}
+
+func (conn *Connection) ForeignKeys(tableName string) (keys []*ForeignKey, err error) {
+	const sql = `
+		SELECT rc.rdb$constraint_name, rc.rdb$index_name, uq.rdb$relation_name, uq.rdb$index_name,
+			ref.rdb$update_rule, ref.rdb$delete_rule
+		FROM rdb$relation_constraints rc
+		JOIN rdb$ref_constraints ref ON rc.rdb$constraint_name = ref.rdb$constraint_name
+		JOIN rdb$relation_constraints uq ON ref.rdb$const_name_uq = uq.rdb$constraint_name
+		WHERE rc.rdb$relation_name = ? AND rc.rdb$constraint_type = 'FOREIGN KEY'
+		ORDER BY rc.rdb$constraint_name`
+	tableName = conn.storedName(tableName)
+	var cursor *Cursor
+	if cursor, err = conn.Execute(sql, tableName); err != nil {
+		return
+	}
+	defer cursor.Close()
+
+	for cursor.Next() {
+		var key ForeignKey
+		var index, referencedIndex string
+		if err = cursor.Scan(&key.Name, &index, &key.ReferencedTable, &referencedIndex, &key.OnUpdate, &key.OnDelete); err != nil {
+			return
+		}
+		key.Name = conn.metaName(key.Name)
+		key.TableName = conn.metaName(tableName)
+		key.ReferencedTable = conn.metaName(key.ReferencedTable)
+		key.OnUpdate = strings.TrimRightFunc(key.OnUpdate, unicode.IsSpace)
+		key.OnDelete = strings.TrimRightFunc(key.OnDelete, unicode.IsSpace)
+		if key.Columns, err = conn.IndexColumns(strings.TrimRightFunc(index, unicode.IsSpace)); err != nil {
+			return
+		}
+		if key.ReferencedColumns, err = conn.IndexColumns(strings.TrimRightFunc(referencedIndex, unicode.IsSpace)); err != nil {
+			return
+		}
+		keys = append(keys, &key)
+	}
+	if cursor.Err() != io.EOF {
+		err = cursor.Err()
+	}
+	return
+}
+
+func (conn *Connection) UniqueConstraints(tableName string) (constraints []*UniqueConstraint, err error) {
+	const sql = `
+		SELECT rdb$constraint_name, rdb$index_name
+		FROM rdb$relation_constraints
+		WHERE rdb$relation_name = ? AND rdb$constraint_type = 'UNIQUE'
+		ORDER BY rdb$constraint_name`
+	tableName = conn.storedName(tableName)
+	var cursor *Cursor
+	if cursor, err = conn.Execute(sql, tableName); err != nil {
+		return
+	}
+	defer cursor.Close()
+
+	for cursor.Next() {
+		var constraint UniqueConstraint
+		var index string
+		if err = cursor.Scan(&constraint.Name, &index); err != nil {
+			return
+		}
+		constraint.Name = conn.metaName(constraint.Name)
+		constraint.TableName = conn.metaName(tableName)
+		if constraint.Columns, err = conn.IndexColumns(strings.TrimRightFunc(index, unicode.IsSpace)); err != nil {
+			return
+		}
+		constraints = append(constraints, &constraint)
+	}
+	if cursor.Err() != io.EOF {
+		err = cursor.Err()
+	}
+	return
+}
+
+// CheckConstraints returns the source of each CHECK constraint, such as
+// "CHECK (QTY > 0)". Firebird keeps it in the constraint's before insert
+// trigger.
+func (conn *Connection) CheckConstraints(tableName string) (constraints []*CheckConstraint, err error) {
+	const sql = `
+		SELECT rc.rdb$constraint_name, t.rdb$trigger_source
+		FROM rdb$relation_constraints rc
+		JOIN rdb$check_constraints cc ON rc.rdb$constraint_name = cc.rdb$constraint_name
+		JOIN rdb$triggers t ON cc.rdb$trigger_name = t.rdb$trigger_name
+		WHERE rc.rdb$relation_name = ? AND rc.rdb$constraint_type = 'CHECK' AND t.rdb$trigger_type = 1
+		ORDER BY rc.rdb$constraint_name`
+	tableName = conn.storedName(tableName)
+	var cursor *Cursor
+	if cursor, err = conn.Execute(sql, tableName); err != nil {
+		return
+	}
+	defer cursor.Close()
+
+	for cursor.Next() {
+		var constraint CheckConstraint
+		var source NullableString
+		if err = cursor.Scan(&constraint.Name, &source); err != nil {
+			return
+		}
+		constraint.Name = conn.metaName(constraint.Name)
+		constraint.TableName = conn.metaName(tableName)
+		constraint.Source = strings.TrimSpace(source.Value)
+		constraints = append(constraints, &constraint)
+	}
+	if cursor.Err() != io.EOF {
+		err = cursor.Err()
+	}
+	return
+}
@@ -9,10 +9,13 @@ package fb
 import "C"
 
 import (
+	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io"
 	"math"
+	"math/big"
 	"runtime"
 	"strings"
 	"time"
@@ -36,6 +39,17 @@ type Cursor struct {
 	err           error
 	row, lastRow  Row
 	lastRowMap    map[string]interface{}
+	statement     *Statement
+	statementType C.long
+	transaction   *Transaction
+	arrayDescs    map[string]*C.ISC_ARRAY_DESC
+	// singleton cursors hold the row returned by execute in output until the
+	// first Next; there is no server-side cursor to fetch from or close
+	singleton   bool
+	output      Row
+	dml         bool
+	watch       *contextWatch
+	StreamBlobs bool
 }
 
 const sqlda_colsinit = 50
@@ -61,13 +75,17 @@ func (cursor *Cursor) execute(sql string, args ...interface{}) (rowsAffected int
 	var isc_status [20]C.ISC_STATUS
 
 	if cursor.open {
-		C.isc_dsql_free_statement(&isc_status[0], &cursor.stmt, C.DSQL_close)
-		if err = fbErrorCheck(&isc_status); err != nil {
-			return
+		if !cursor.singleton {
+			C.isc_dsql_free_statement(&isc_status[0], &cursor.stmt, C.DSQL_close)
+			if err = fbErrorCheck(&isc_status); err != nil {
+				return
+			}
 		}
 		cursor.open = false
+		cursor.singleton = false
+		cursor.output = nil
 	}
-	if cursor.connection.TransactionStarted() {
+	if cursor.transaction != nil || cursor.connection.TransactionStarted() {
 		rowsAffected, err = cursor.execute2(sql, args...)
 	} else {
 		if err = cursor.connection.TransactionStart(""); err != nil {
@@ -82,7 +100,7 @@ func (cursor *Cursor) execute(sql string, args ...interface{}) (rowsAffected int
 			err = cursor.connection.Commit()
 		}
 	}
-	if !cursor.open {
+	if !cursor.open && cursor.statement == nil {
 		cursor.close()
 	}
 	return
@@ -90,14 +108,32 @@ func (cursor *Cursor) execute(sql string, args ...interface{}) (rowsAffected int
 
 const nullTerminated = 0
 
+func (cursor *Cursor) transactHandle() *C.isc_tr_handle {
+	if cursor.transaction != nil {
+		return &cursor.transaction.transact
+	}
+	return &cursor.connection.transact
+}
+
 func (cursor *Cursor) execute2(sql string, args ...interface{}) (rowsAffected int, err error) {
+	if cursor.statement == nil {
+		if err = cursor.prepare(sql); err != nil {
+			return
+		}
+	}
+	return cursor.executePrepared(args)
+}
+
+func (cursor *Cursor) prepare(sql string) (err error) {
 	var isc_status [20]C.ISC_STATUS
 
+	cursor.arrayDescs = nil
+	cursor.dml = reDMLStatement.MatchString(sql)
 	// prepare query
 	sql2 := C.CString(sql)
 	defer C.free(unsafe.Pointer(sql2))
 	sql3 := (*C.ISC_SCHAR)(unsafe.Pointer(sql2))
-	C.isc_dsql_prepare(&isc_status[0], &cursor.connection.transact, &cursor.stmt, nullTerminated, sql3, C.SQL_DIALECT_CURRENT, cursor.o_sqlda)
+	C.isc_dsql_prepare(&isc_status[0], cursor.transactHandle(), &cursor.stmt, nullTerminated, sql3, C.SQL_DIALECT_CURRENT, cursor.o_sqlda)
 	if err = fbErrorCheck(&isc_status); err != nil {
 		return
 	}
@@ -111,12 +147,11 @@ func (cursor *Cursor) execute2(sql string, args ...interface{}) (rowsAffected in
 		return
 	}
 
-	var statement C.long
 	if isc_info_buff[0] == C.isc_info_sql_stmt_type {
 		length := C.isc_vax_integer(&isc_info_buff[1], 2)
-		statement = C.long(C.isc_vax_integer(&isc_info_buff[3], C.short(length)))
+		cursor.statementType = C.long(C.isc_vax_integer(&isc_info_buff[3], C.short(length)))
 	} else {
-		statement = 0
+		cursor.statementType = 0
 	}
 	// describe input parameters
 	C.isc_dsql_describe_bind(&isc_status[0], &cursor.stmt, C.SQLDA_VERSION1, cursor.i_sqlda)
@@ -147,20 +182,29 @@ func (cursor *Cursor) execute2(sql string, args ...interface{}) (rowsAffected in
 			cursor.i_buffer_size = length
 		}
 	}
-	if cursor.o_sqlda.sqld != 0 {
-		// open cursor if statement is query
-		// get number of columns and reallocate SQLDA
-		cols := cursor.o_sqlda.sqld
-		if cursor.o_sqlda.sqln < cols {
-			C.free(unsafe.Pointer(cursor.o_sqlda))
-			cursor.o_sqlda = C.sqlda_alloc(C.long(cols))
-			// describe again
-			C.isc_dsql_describe(&isc_status[0], &cursor.stmt, C.SQLDA_VERSION1, cursor.o_sqlda)
-			if err = fbErrorCheck(&isc_status); err != nil {
-				return
-			}
+	// get number of columns and reallocate SQLDA
+	cols := cursor.o_sqlda.sqld
+	if cursor.o_sqlda.sqln < cols {
+		C.free(unsafe.Pointer(cursor.o_sqlda))
+		cursor.o_sqlda = C.sqlda_alloc(C.long(cols))
+		// describe again
+		C.isc_dsql_describe(&isc_status[0], &cursor.stmt, C.SQLDA_VERSION1, cursor.o_sqlda)
+		if err = fbErrorCheck(&isc_status); err != nil {
+			return
 		}
+	}
+	return
+}
+
+func (cursor *Cursor) executePrepared(args []interface{}) (rowsAffected int, err error) {
+	var isc_status [20]C.ISC_STATUS
 
+	in_params := cursor.i_sqlda.sqld
+	if cursor.o_sqlda.sqld != 0 && cursor.statementType != C.isc_info_sql_stmt_select && cursor.statementType != C.isc_info_sql_stmt_select_for_upd {
+		// EXECUTE PROCEDURE and DML with RETURNING produce a single row
+		return cursor.executeSingleton(args)
+	} else if cursor.o_sqlda.sqld != 0 {
+		// open cursor if statement is query
 		var i_sqlda *C.XSQLDA
 		if in_params > 0 {
 			if err = cursor.setInputParams(args); err != nil {
@@ -172,24 +216,16 @@ func (cursor *Cursor) execute2(sql string, args ...interface{}) (rowsAffected in
 		}
 
 		// open cursor
-		C.isc_dsql_execute2(&isc_status[0], &cursor.connection.transact, &cursor.stmt, C.SQLDA_VERSION1, i_sqlda, (*C.XSQLDA)(nil))
+		C.isc_dsql_execute2(&isc_status[0], cursor.transactHandle(), &cursor.stmt, C.SQLDA_VERSION1, i_sqlda, (*C.XSQLDA)(nil))
 		if err = fbErrorCheck(&isc_status); err != nil {
 			return
 		}
 		cursor.open = true
-
-		// get size of results buffer and reallocate it
-		length := C.calculate_buffsize(cursor.o_sqlda)
-		if length > cursor.o_buffer_size {
-			cursor.o_buffer = (*C.char)(C.realloc(unsafe.Pointer(cursor.o_buffer), C.size_t(length)))
-			cursor.o_buffer_size = length
-		}
-
-		// Set the description attributes
-		cursor.Columns = columnsFromSqlda(cursor.o_sqlda, cursor.connection.database.LowercaseNames)
-		cursor.ColumnsMap = columnsMapFromSlice(cursor.Columns)
+		cursor.eof = false
+		cursor.allocOutput()
 	} else {
 		// execute statement if not query
+		statement := cursor.statementType
 		if statement == C.isc_info_sql_stmt_start_trans {
 			panic("use fb.Connection.Transaction()")
 		} else if statement == C.isc_info_sql_stmt_commit {
@@ -200,12 +236,12 @@ func (cursor *Cursor) execute2(sql string, args ...interface{}) (rowsAffected in
 			if err = cursor.setInputParams(args); err != nil {
 				return
 			}
-			C.isc_dsql_execute2(&isc_status[0], &cursor.connection.transact, &cursor.stmt, C.SQLDA_VERSION1, cursor.i_sqlda, (*C.XSQLDA)(nil))
+			C.isc_dsql_execute2(&isc_status[0], cursor.transactHandle(), &cursor.stmt, C.SQLDA_VERSION1, cursor.i_sqlda, (*C.XSQLDA)(nil))
 			if err = fbErrorCheck(&isc_status); err != nil {
 				return
 			}
 		} else {
-			C.isc_dsql_execute2(&isc_status[0], &cursor.connection.transact, &cursor.stmt, C.SQLDA_VERSION1, (*C.XSQLDA)(nil), (*C.XSQLDA)(nil))
+			C.isc_dsql_execute2(&isc_status[0], cursor.transactHandle(), &cursor.stmt, C.SQLDA_VERSION1, (*C.XSQLDA)(nil), (*C.XSQLDA)(nil))
 			if err = fbErrorCheck(&isc_status); err != nil {
 				return
 			}
@@ -234,6 +270,59 @@ func finalize(cursor *Cursor) {
 	}
 }
 
+// allocOutput sizes the output buffer and describes the result columns.
+func (cursor *Cursor) allocOutput() {
+	// get size of results buffer and reallocate it
+	length := C.calculate_buffsize(cursor.o_sqlda)
+	if length > cursor.o_buffer_size {
+		cursor.o_buffer = (*C.char)(C.realloc(unsafe.Pointer(cursor.o_buffer), C.size_t(length)))
+		cursor.o_buffer_size = length
+	}
+
+	// Set the description attributes
+	cursor.Columns = columnsFromSqlda(cursor.o_sqlda, cursor.connection.database.LowercaseNames)
+	cursor.ColumnsMap = columnsMapFromSlice(cursor.Columns)
+}
+
+// executeSingleton runs a statement that returns one row without opening a
+// server-side cursor. The row is kept in output and the cursor is left open so
+// that Next returns it.
+func (cursor *Cursor) executeSingleton(args []interface{}) (rowsAffected int, err error) {
+	var isc_status [20]C.ISC_STATUS
+
+	var i_sqlda *C.XSQLDA
+	if cursor.i_sqlda.sqld > 0 {
+		if err = cursor.setInputParams(args); err != nil {
+			return
+		}
+		i_sqlda = cursor.i_sqlda
+	}
+	cursor.output = nil
+	cursor.allocOutput()
+	if err = cursor.bindOutput(); err != nil {
+		return
+	}
+	C.isc_dsql_execute2(&isc_status[0], cursor.transactHandle(), &cursor.stmt, C.SQLDA_VERSION1, i_sqlda, cursor.o_sqlda)
+	if err = fbErrorCheck(&isc_status); err != nil {
+		return
+	}
+	if err = cursor.readRow(); err != nil {
+		return
+	}
+	if rowsAffected, err = cursor.rowsAffected(cursor.statementType); err != nil {
+		return
+	}
+	// an UPDATE or DELETE that matched nothing still returns a row of NULLs
+	if rowsAffected != 0 || !cursor.dml {
+		cursor.output = make(Row, cursor.o_sqlda.sqld)
+		copy(cursor.output, cursor.row)
+	}
+	cursor.open = true
+	cursor.eof = false
+	cursor.singleton = true
+	return
+}
+
 func (cursor *Cursor) setInputParams(args []interface{}) (err error) {
 	if int(cursor.i_sqlda.sqld) != len(args) {
 		return errors.New(fmt.Sprintf("statement requires %d items; %d given", cursor.i_sqlda.sqld, len(args)))
@@ -293,13 +382,12 @@ func (cursor *Cursor) setInputParams(args []interface{}) (err error) {
 				offset = fbAlign(offset, alignment)
 				ivar.sqldata = (*C.ISC_SCHAR)(unsafe.Pointer(uintptr(unsafe.Pointer(cursor.i_buffer)) + uintptr(offset)))
 				if ivar.sqlscale < 0 {
-					var dvalue float64
-					dvalue, err = float64FromIf(arg)
+					var ivalue int64
+					ivalue, err = scaledInt64FromIf(arg, -int(ivar.sqlscale))
 					if err != nil {
 						return
 					}
-					dvalue *= math.Pow10(-int(ivar.sqlscale))
-					lvalue = C.ISC_LONG(int64(dvalue))
+					lvalue = C.ISC_LONG(ivalue)
 				} else {
 					var ivalue int64
 					ivalue, err = int64FromIf(arg)
@@ -320,13 +408,12 @@ func (cursor *Cursor) setInputParams(args []interface{}) (err error) {
 				offset = fbAlign(offset, alignment)
 				ivar.sqldata = (*C.ISC_SCHAR)(unsafe.Pointer(uintptr(unsafe.Pointer(cursor.i_buffer)) + uintptr(offset)))
 				if ivar.sqlscale < 0 {
-					var dvalue float64
-					dvalue, err = float64FromIf(arg)
+					var ivalue int64
+					ivalue, err = scaledInt64FromIf(arg, -int(ivar.sqlscale))
 					if err != nil {
 						return
 					}
-					dvalue *= math.Pow10(-int(ivar.sqlscale))
-					lvalue = C.ISC_LONG(int64(dvalue))
+					lvalue = C.ISC_LONG(ivalue)
 				} else {
 					var ivalue int64
 					ivalue, err = int64FromIf(arg)
@@ -383,13 +470,12 @@ func (cursor *Cursor) setInputParams(args []interface{}) (err error) {
 				ivar.sqldata = (*C.ISC_SCHAR)(unsafe.Pointer(uintptr(unsafe.Pointer(cursor.i_buffer)) + uintptr(offset)))
 
 				if ivar.sqlscale < 0 {
-					var dvalue float64
-					dvalue, err = float64FromIf(arg)
+					var ivalue int64
+					ivalue, err = scaledInt64FromIf(arg, -int(ivar.sqlscale))
 					if err != nil {
 						return
 					}
-					dvalue *= math.Pow10(-int(ivar.sqlscale))
-					llvalue = C.ISC_INT64(int64(dvalue))
+					llvalue = C.ISC_INT64(ivalue)
 				} else {
 					var ivalue int64
 					ivalue, err = int64FromIf(arg)
@@ -405,45 +491,35 @@ func (cursor *Cursor) setInputParams(args []interface{}) (err error) {
 				offset = fbAlign(offset, alignment)
 				ivar.sqldata = (*C.ISC_SCHAR)(unsafe.Pointer(uintptr(unsafe.Pointer(cursor.i_buffer)) + uintptr(offset)))
 
-				var bs []byte
-				bs, err = bytesFromIf(arg)
-				if err != nil {
-					return
+				var r io.Reader
+				if reader, ok := arg.(io.Reader); ok {
+					r = reader
+				} else {
+					var bs []byte
+					bs, err = bytesFromIf(arg)
+					if err != nil {
+						return
+					}
+					r = bytes.NewReader(bs)
 				}
-
-				var blobHandle C.isc_blob_handle = 0
 				var blobId C.ISC_QUAD
-				var isc_status [20]C.ISC_STATUS
-
-				C.isc_create_blob2(
-					&isc_status[0], &cursor.connection.db, &cursor.connection.transact,
-					&blobHandle, &blobId, 0, (*C.ISC_SCHAR)(nil))
-				if err = fbErrorCheck(&isc_status); err != nil {
-					return
-				}
-				length := len(bs)
-				i := 0
-				for length >= 4096 && err == nil {
-					C.isc_put_segment(&isc_status[0], &blobHandle, 4096, (*C.ISC_SCHAR)(unsafe.Pointer(&bs[i])))
-					err = fbErrorCheck(&isc_status)
-					i += 4096
-					length -= 4096
-				}
-				if length > 0 && err == nil {
-					C.isc_put_segment(&isc_status[0], &blobHandle, C.ushort(length), (*C.ISC_SCHAR)(unsafe.Pointer(&bs[i])))
-					err = fbErrorCheck(&isc_status)
-				}
-				if err != nil {
-					return
-				}
-				C.isc_close_blob(&isc_status[0], &blobHandle)
-				if err = fbErrorCheck(&isc_status); err != nil {
+				if blobId, err = writeBlob(cursor.connection, cursor.transactHandle(), r); err != nil {
 					return
 				}
 
 				*(*C.ISC_QUAD)(unsafe.Pointer(ivar.sqldata)) = blobId
 				offset += alignment
 
+			case C.SQL_ARRAY:
+				offset = fbAlign(offset, alignment)
+				ivar.sqldata = (*C.ISC_SCHAR)(unsafe.Pointer(uintptr(unsafe.Pointer(cursor.i_buffer)) + uintptr(offset)))
+				var arrayId C.ISC_QUAD
+				if arrayId, err = cursor.writeArray(ivar, arg); err != nil {
+					return
+				}
+				*(*C.ISC_QUAD)(unsafe.Pointer(ivar.sqldata)) = arrayId
+				offset += alignment
+
 			case C.SQL_TIMESTAMP:
 				offset = fbAlign(offset, alignment)
 				ivar.sqldata = (*C.ISC_SCHAR)(unsafe.Pointer(uintptr(unsafe.Pointer(cursor.i_buffer)) + uintptr(offset)))
@@ -479,6 +555,100 @@ func (cursor *Cursor) setInputParams(args []interface{}) (err error) {
 				isc_ts := timestampFromTime(tvalue, cursor.connection.Location)
 				*(*C.ISC_TIMESTAMP)(unsafe.Pointer(ivar.sqldata)) = isc_ts
 				offset += alignment
+			case C.SQL_BOOLEAN:
+				offset = fbAlign(offset, alignment)
+				ivar.sqldata = (*C.ISC_SCHAR)(unsafe.Pointer(uintptr(unsafe.Pointer(cursor.i_buffer)) + uintptr(offset)))
+				var bvalue bool
+				if bvalue, err = boolFromIf(arg); err != nil {
+					return
+				}
+				if bvalue {
+					*(*C.uchar)(unsafe.Pointer(ivar.sqldata)) = 1
+				} else {
+					*(*C.uchar)(unsafe.Pointer(ivar.sqldata)) = 0
+				}
+				offset += alignment
+
+			case C.SQL_INT128:
+				offset = fbAlign(offset, alignment)
+				ivar.sqldata = (*C.ISC_SCHAR)(unsafe.Pointer(uintptr(unsafe.Pointer(cursor.i_buffer)) + uintptr(offset)))
+				var dvalue Decimal
+				if dvalue, err = decimalFromIf(arg); err != nil {
+					return
+				}
+				var lo, hi uint64
+				if lo, hi, err = int128FromBigInt(dvalue.Rescale(-int(ivar.sqlscale)).Unscaled); err != nil {
+					return
+				}
+				i128 := (*C.FB_I128)(unsafe.Pointer(ivar.sqldata))
+				i128.fb_data[0], i128.fb_data[1] = C.ISC_UINT64(lo), C.ISC_UINT64(hi)
+				offset += alignment
+
+			case C.SQL_DEC16, C.SQL_DEC34:
+				offset = fbAlign(offset, alignment)
+				ivar.sqldata = (*C.ISC_SCHAR)(unsafe.Pointer(uintptr(unsafe.Pointer(cursor.i_buffer)) + uintptr(offset)))
+				var dvalue Decimal
+				if dvalue, err = decimalFromIf(arg); err != nil {
+					return
+				}
+				var w *big.Int
+				if dtp == C.SQL_DEC16 {
+					if w, err = encodeDecFloat(decFloat16, dvalue); err != nil {
+						return
+					}
+					dec := (*C.FB_DEC16)(unsafe.Pointer(ivar.sqldata))
+					dec.fb_data[0] = C.ISC_UINT64(w.Uint64())
+				} else {
+					if w, err = encodeDecFloat(decFloat34, dvalue); err != nil {
+						return
+					}
+					lo, hi := bigToWords(w)
+					dec := (*C.FB_DEC34)(unsafe.Pointer(ivar.sqldata))
+					dec.fb_data[0], dec.fb_data[1] = C.ISC_UINT64(lo), C.ISC_UINT64(hi)
+				}
+				offset += alignment
+
+			case C.SQL_TIMESTAMP_TZ, C.SQL_TIMESTAMP_TZ_EX:
+				offset = fbAlign(offset, C.ISC_SHORT(unsafe.Sizeof(C.ISC_TIME(0))))
+				ivar.sqldata = (*C.ISC_SCHAR)(unsafe.Pointer(uintptr(unsafe.Pointer(cursor.i_buffer)) + uintptr(offset)))
+				var tvalue time.Time
+				if tvalue, err = timeFromIf(arg, cursor.connection.Location); err != nil {
+					return
+				}
+				var zone uint16
+				if zone, err = cursor.timeZoneFromTime(tvalue); err != nil {
+					return
+				}
+				ts := (*C.ISC_TIMESTAMP_TZ_EX)(unsafe.Pointer(ivar.sqldata))
+				ts.utc_timestamp = timestampFromTime(tvalue.UTC(), time.UTC)
+				ts.time_zone = C.ISC_USHORT(zone)
+				if dtp == C.SQL_TIMESTAMP_TZ_EX {
+					_, zoneOffset := tvalue.Zone()
+					ts.ext_offset = C.ISC_SHORT(zoneOffset / 60)
+				}
+				offset += ivar.sqllen
+
+			case C.SQL_TIME_TZ, C.SQL_TIME_TZ_EX:
+				offset = fbAlign(offset, C.ISC_SHORT(unsafe.Sizeof(C.ISC_TIME(0))))
+				ivar.sqldata = (*C.ISC_SCHAR)(unsafe.Pointer(uintptr(unsafe.Pointer(cursor.i_buffer)) + uintptr(offset)))
+				var tvalue time.Time
+				if tvalue, err = timeFromIf(arg, cursor.connection.Location); err != nil {
+					return
+				}
+				tvalue = time.Date(2020, 1, 1, tvalue.Hour(), tvalue.Minute(), tvalue.Second(), tvalue.Nanosecond(), tvalue.Location())
+				var zone uint16
+				if zone, err = cursor.timeZoneFromTime(tvalue); err != nil {
+					return
+				}
+				tm := (*C.ISC_TIME_TZ_EX)(unsafe.Pointer(ivar.sqldata))
+				tm.utc_time = iscTimeFromTime(tvalue.UTC(), time.UTC)
+				tm.time_zone = C.ISC_USHORT(zone)
+				if dtp == C.SQL_TIME_TZ_EX {
+					_, zoneOffset := tvalue.Zone()
+					tm.ext_offset = C.ISC_SHORT(zoneOffset / 60)
+				}
+				offset += ivar.sqllen
+
 			default:
 				panic("Shouldn't reach here! (dtp not implemented)")
 			}
@@ -509,7 +679,7 @@ func (cursor *Cursor) executeWithParams(args []interface{}) (err error) {
 	if err = cursor.setInputParams(args); err != nil {
 		return
 	}
-	C.isc_dsql_execute2(&isc_status[0], &cursor.connection.transact, &cursor.stmt, C.SQLDA_VERSION1, cursor.i_sqlda, (*C.XSQLDA)(nil))
+	C.isc_dsql_execute2(&isc_status[0], cursor.transactHandle(), &cursor.stmt, C.SQLDA_VERSION1, cursor.i_sqlda, (*C.XSQLDA)(nil))
 	return fbErrorCheck(&isc_status)
 }
 
@@ -637,16 +807,24 @@ func (cursor *Cursor) Close() (err error) {
 func (cursor *Cursor) close() (err error) {
 	var isc_status [20]C.ISC_STATUS
 
-	C.isc_dsql_free_statement(&isc_status[0], &cursor.stmt, C.DSQL_close)
-	if err = fbErrorCheckWarn(&isc_status); err != nil {
-		return
+	cursor.stopWatch()
+	if !cursor.singleton {
+		C.isc_dsql_free_statement(&isc_status[0], &cursor.stmt, C.DSQL_close)
+		if err = fbErrorCheckWarn(&isc_status); err != nil {
+			return
+		}
 	}
-	C.isc_dsql_free_statement(&isc_status[0], &cursor.stmt, C.DSQL_drop)
-	if err = fbErrorCheck(&isc_status); err != nil {
-		return
+	// statements prepared through Connection.Prepare are dropped by Statement.Close
+	if cursor.statement == nil {
+		C.isc_dsql_free_statement(&isc_status[0], &cursor.stmt, C.DSQL_drop)
+		if err = fbErrorCheck(&isc_status); err != nil {
+			return
+		}
 	}
 	cursor.open = false
-	if cursor.connection.transact == cursor.auto_transact {
+	cursor.singleton = false
+	cursor.output = nil
+	if cursor.transaction == nil && cursor.connection.transact == cursor.auto_transact {
 		err = cursor.connection.Commit()
 		cursor.auto_transact = cursor.connection.transact
 	}
@@ -656,14 +834,19 @@ func (cursor *Cursor) close() (err error) {
 }
 
 func (cursor *Cursor) prep() (err error) {
-	var isc_status [20]C.ISC_STATUS
-
 	if err = cursor.check(); err != nil {
 		return
 	}
 	if err = cursor.connection.check(); err != nil {
 		return
 	}
+	return cursor.bindOutput()
+}
+
+// bindOutput points the output SQLDA at the output buffer.
+func (cursor *Cursor) bindOutput() (err error) {
+	var isc_status [20]C.ISC_STATUS
+
 	C.isc_dsql_describe(&isc_status[0], &cursor.stmt, C.SQLDA_VERSION1, cursor.o_sqlda)
 	if err = fbErrorCheck(&isc_status); err != nil {
 		return
@@ -679,6 +862,8 @@ func (cursor *Cursor) prep() (err error) {
 		} else if dtp == C.SQL_VARYING {
 			length += C.SHORT_SIZE
 			alignment = C.SHORT_SIZE
+		} else if dtp == C.SQL_TIMESTAMP_TZ || dtp == C.SQL_TIMESTAMP_TZ_EX {
+			alignment = C.ISC_SHORT(unsafe.Sizeof(C.ISC_TIME(0)))
 		}
 		offset = fbAlign(offset, alignment)
 		ovar.sqldata = (*C.ISC_SCHAR)(unsafe.Pointer((uintptr(unsafe.Pointer(cursor.o_buffer)) + uintptr(offset))))
@@ -694,12 +879,6 @@ func (cursor *Cursor) Err() error {
 	return cursor.err
 }
 
-var blobItemsFetch = [...]C.ISC_SCHAR{
-	C.isc_info_blob_max_segment,
-	C.isc_info_blob_num_segments,
-	C.isc_info_blob_total_length,
-}
-
 func (cursor *Cursor) Next() bool {
 	const SQLCODE_NOMORE = 100
 	var isc_status [20]C.ISC_STATUS
@@ -714,6 +893,16 @@ func (cursor *Cursor) Next() bool {
 		cursor.err = &Error{Message: "Cursor is past end of data."}
 		return false
 	}
+	if cursor.singleton {
+		if cursor.output == nil {
+			cursor.eof = true
+			cursor.err = io.EOF
+			return false
+		}
+		cursor.row, cursor.output = cursor.output, nil
+		cursor.lastRow, cursor.lastRowMap = nil, nil
+		return true
+	}
 	// fetch one row
 	if C.isc_dsql_fetch(&isc_status[0], &cursor.stmt, C.SQLDA_VERSION1, cursor.o_sqlda) == SQLCODE_NOMORE {
 		cursor.eof = true
@@ -723,6 +912,12 @@ func (cursor *Cursor) Next() bool {
 	if cursor.err = fbErrorCheck(&isc_status); cursor.err != nil {
 		return false
 	}
+	cursor.err = cursor.readRow()
+	return cursor.err == nil
+}
+
+// readRow converts the fetched output buffer into cursor.row.
+func (cursor *Cursor) readRow() (err error) {
 	// create result tuple
 	cols := cursor.o_sqlda.sqld
 	if len(cursor.row) < int(cols) {
@@ -751,14 +946,14 @@ func (cursor *Cursor) Next() bool {
 			case C.SQL_SHORT:
 				sval := *(*C.short)(unsafe.Pointer(sqlvar.sqldata))
 				if sqlvar.sqlscale < 0 {
-					val = float64(sval) / math.Pow10(-int(sqlvar.sqlscale))
+					val = cursor.scaledValue(int64(sval), sqlvar.sqlscale)
 				} else {
 					val = int16(sval)
 				}
 			case C.SQL_LONG:
 				lval := *(*C.ISC_LONG)(unsafe.Pointer(sqlvar.sqldata))
 				if sqlvar.sqlscale < 0 {
-					val = float64(lval) / math.Pow10(-int(sqlvar.sqlscale))
+					val = cursor.scaledValue(int64(lval), sqlvar.sqlscale)
 				} else {
 					val = int32(lval)
 				}
@@ -774,7 +969,7 @@ func (cursor *Cursor) Next() bool {
 				// fmt.Println("Fetch SQL_INT64")
 				ival := *(*C.ISC_INT64)(unsafe.Pointer(sqlvar.sqldata))
 				if sqlvar.sqlscale < 0 {
-					val = float64(ival) / math.Pow10(-int(sqlvar.sqlscale))
+					val = cursor.scaledValue(int64(ival), sqlvar.sqlscale)
 				} else {
 					val = int64(ival)
 				}
@@ -789,63 +984,94 @@ func (cursor *Cursor) Next() bool {
 				val = timeFromIscDate(isc_dt, cursor.connection.Location)
 			case C.SQL_BLOB:
 				// fmt.Println("Fetch SQL_BLOB")
-				var blobHandle C.isc_blob_handle = 0
-				var blobID C.ISC_QUAD = *(*C.ISC_QUAD)(unsafe.Pointer(sqlvar.sqldata))
-				C.isc_open_blob2(&isc_status[0], &cursor.connection.db, &cursor.connection.transact, &blobHandle, &blobID, 0, (*C.ISC_UCHAR)(nil))
-				if cursor.err = fbErrorCheck(&isc_status); cursor.err != nil {
-					return false
-				}
-				var blobInfo [32]C.ISC_SCHAR
-				C.isc_blob_info(
-					&isc_status[0], &blobHandle,
-					C.short(unsafe.Sizeof(blobItemsFetch)), &blobItemsFetch[0],
-					C.short(unsafe.Sizeof(blobInfo)), &blobInfo[0])
-				if cursor.err = fbErrorCheck(&isc_status); cursor.err != nil {
-					return false
-				}
-				var length C.short
-				var maxSegment C.ISC_LONG = 0
-				var numSegments C.ISC_LONG = 0
-				var totalLength C.ISC_LONG = 0
-				var actualSegLen C.ushort
-				for i := 0; blobInfo[i] != C.isc_info_end; i += int(length) {
-					item := blobInfo[i]
-					i += 1
-					length = C.short(C.isc_vax_integer(&blobInfo[i], 2))
-					i += 2
-					switch item {
-					case C.isc_info_blob_max_segment:
-						maxSegment = C.isc_vax_integer(&blobInfo[i], length)
-					case C.isc_info_blob_num_segments:
-						numSegments = C.isc_vax_integer(&blobInfo[i], length)
-					case C.isc_info_blob_total_length:
-						totalLength = C.isc_vax_integer(&blobInfo[i], length)
-					}
+				blobID := *(*C.ISC_QUAD)(unsafe.Pointer(sqlvar.sqldata))
+				br := newBlobReader(cursor.connection, cursor.transactHandle(), blobID)
+				if cursor.StreamBlobs {
+					val = br
+					break
 				}
-				bval := make([]byte, totalLength)
-				for i := C.ISC_LONG(0); i < totalLength && numSegments > 0; numSegments-- {
-					C.isc_get_segment(
-						&isc_status[0], &blobHandle, &actualSegLen,
-						C.ushort(maxSegment), (*C.ISC_SCHAR)(unsafe.Pointer(&bval[i])))
-					if cursor.err = fbErrorCheck(&isc_status); cursor.err != nil {
-						return false
-					}
-					i += C.ISC_LONG(actualSegLen)
-				}
-				C.isc_close_blob(&isc_status[0], &blobHandle)
-				if cursor.err = fbErrorCheck(&isc_status); cursor.err != nil {
-					return false
+				var bval []byte
+				if bval, err = br.readAll(); err != nil {
+					return
 				}
 				if cursor.Columns[count].SqlSubtype.Value == 1 {
 					val = string(bval)
 				} else {
 					val = bval
 				}
+			case C.SQL_ARRAY:
+				if val, err = cursor.arrayValue(sqlvar); err != nil {
+					return
+				}
+			case C.SQL_BOOLEAN:
+				val = *(*C.uchar)(unsafe.Pointer(sqlvar.sqldata)) != 0
+			case C.SQL_INT128:
+				i128 := (*C.FB_I128)(unsafe.Pointer(sqlvar.sqldata))
+				bval := bigIntFromInt128(uint64(i128.fb_data[0]), uint64(i128.fb_data[1]))
+				if sqlvar.sqlscale < 0 {
+					val = cursor.decimalValue(Decimal{bval, -int(sqlvar.sqlscale)})
+				} else {
+					val = bval
+				}
+			case C.SQL_DEC16:
+				dec := (*C.FB_DEC16)(unsafe.Pointer(sqlvar.sqldata))
+				val = cursor.decFloatValue(decFloat16, wordsToBig(uint64(dec.fb_data[0]), 0))
+			case C.SQL_DEC34:
+				dec := (*C.FB_DEC34)(unsafe.Pointer(sqlvar.sqldata))
+				val = cursor.decFloatValue(decFloat34, wordsToBig(uint64(dec.fb_data[0]), uint64(dec.fb_data[1])))
+			case C.SQL_TIMESTAMP_TZ, C.SQL_TIMESTAMP_TZ_EX:
+				ts := (*C.ISC_TIMESTAMP_TZ_EX)(unsafe.Pointer(sqlvar.sqldata))
+				var loc *time.Location
+				if loc, err = cursor.locationFromTimeZone(uint16(ts.time_zone), int(ts.ext_offset), dtp == C.SQL_TIMESTAMP_TZ_EX); err != nil {
+					return
+				}
+				val = timeFromTimestamp(ts.utc_timestamp, time.UTC).In(loc)
+			case C.SQL_TIME_TZ, C.SQL_TIME_TZ_EX:
+				tm := (*C.ISC_TIME_TZ_EX)(unsafe.Pointer(sqlvar.sqldata))
+				var loc *time.Location
+				if loc, err = cursor.locationFromTimeZone(uint16(tm.time_zone), int(tm.ext_offset), dtp == C.SQL_TIME_TZ_EX); err != nil {
+					return
+				}
+				// Firebird converts TIME WITH TIME ZONE using 2020-01-01 as the date
+				utc := timeFromIscTime(tm.utc_time, time.UTC)
+				val = time.Date(2020, 1, 1, utc.Hour(), utc.Minute(), utc.Second(), utc.Nanosecond(), time.UTC).In(loc)
 			}
 		}
 		cursor.row[count] = val
 	}
-	return true
+	return nil
+}
+
+// NextContext is Next, cancelled when ctx is done. The context is watched
+// from one call to the next until the cursor is closed or exhausted.
+func (cursor *Cursor) NextContext(ctx context.Context) bool {
+	if cursor.watch == nil || cursor.watch.ctx.Done() != ctx.Done() {
+		cursor.stopWatch()
+		cursor.watch = cursor.connection.watchContext(ctx)
+	}
+	if cursor.err = cursor.watch.begin(); cursor.err != nil {
+		cursor.stopWatch()
+		return false
+	}
+	ok := cursor.Next()
+	var err error
+	if !ok && cursor.err != io.EOF {
+		err = cursor.err
+	}
+	if err = cursor.watch.end(err); err != nil {
+		cursor.err = err
+	}
+	if !ok {
+		cursor.stopWatch()
+	}
+	return ok
+}
+
+func (cursor *Cursor) stopWatch() {
+	if cursor.watch != nil {
+		cursor.watch.stop()
+		cursor.watch = nil
+	}
 }
 
 func (cursor *Cursor) Row() Row {
@@ -866,6 +1092,28 @@ func (cursor *Cursor) RowMap() map[string]interface{} {
 	return cursor.lastRowMap
 }
 
+func (cursor *Cursor) scaledValue(unscaled int64, scale C.ISC_SHORT) interface{} {
+	if cursor.connection.database.ExactDecimals {
+		return NewDecimal(unscaled, -int(scale))
+	}
+	return float64(unscaled) / math.Pow10(-int(scale))
+}
+
+func (cursor *Cursor) decimalValue(d Decimal) interface{} {
+	if cursor.connection.database.ExactDecimals {
+		return d
+	}
+	return d.Float64()
+}
+
+func (cursor *Cursor) decFloatValue(f decFloatFormat, w *big.Int) interface{} {
+	d, special, finite := decodeDecFloat(f, w)
+	if !finite {
+		return special
+	}
+	return cursor.decimalValue(d)
+}
+
 func (cursor *Cursor) Scan(dest ...interface{}) error {
 	if cursor.err != nil {
 		return cursor.err
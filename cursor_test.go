@@ -547,3 +547,13 @@ func BenchmarkNextRow(b *testing.B) {
 		cursor.Close()
 	}
 }
+
+func TestDMLStatement(t *testing.T) {
+	st := SuperTest{t}
+	st.True(reDMLStatement.MatchString("update T set A = 1 returning A"))
+	st.True(reDMLStatement.MatchString("  -- note\n/* x */ DELETE FROM T RETURNING A"))
+	st.True(reDMLStatement.MatchString("MERGE INTO T USING S ON 1 = 1 WHEN MATCHED THEN DELETE RETURNING A"))
+	st.False(reDMLStatement.MatchString("EXECUTE PROCEDURE UPDATE_ALL"))
+	st.False(reDMLStatement.MatchString("SELECT * FROM T"))
+	st.False(reDMLStatement.MatchString("UPDATED"))
+}
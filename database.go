@@ -37,6 +37,7 @@ type Database struct {
 	LowercaseNames bool
 	PageSize       int
 	TimeZone       string
+	ExactDecimals  bool
 }
 
 func MapFromConnectionString(parms string) (map[string]string, error) {
@@ -85,7 +86,12 @@ func New(parms string) (db *Database, err error) {
 		}
 	}
 	timezone, _ := p["timezone"]
-	db = &Database{database, username, password, role, charset, lowercaseNames, pageSize, timezone}
+	exactDecimals := false
+	sExactDecimals, ok := p["exact_decimals"]
+	if ok {
+		exactDecimals, _ = strconv.ParseBool(sExactDecimals)
+	}
+	db = &Database{database, username, password, role, charset, lowercaseNames, pageSize, timezone, exactDecimals}
 	return db, nil
 }
 
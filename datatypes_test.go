@@ -3,6 +3,7 @@ package fb
 import (
 	"math/big"
 	"os"
+	"reflect"
 	"strconv"
 	"strings"
 	"testing"
@@ -574,6 +575,54 @@ func TestInsertDecimal(t *testing.T) {
 	st.Equal(float64(12.1), vals[2])
 }
 
+func TestInsertNumericExact(t *testing.T) {
+	st := SuperTest{t}
+	os.Remove(TestFilename)
+
+	conn, err := Create(TestConnectionString + "exact_decimals=true;")
+	if err != nil {
+		t.Fatalf("Unexpected error creating database: %s", err)
+	}
+	defer conn.Drop()
+
+	sqlSchema := "CREATE TABLE TEST (VAL1 NUMERIC(18,4), VAL2 NUMERIC(9,2), VAL3 NUMERIC(3,1));"
+	sqlInsert := "INSERT INTO TEST (VAL1, VAL2, VAL3) VALUES (?, ?, ?);"
+	sqlSelect := "SELECT * FROM TEST;"
+
+	if _, err = conn.Execute(sqlSchema); err != nil {
+		t.Fatalf("Error executing schema: %s", err)
+	}
+
+	money, _ := ParseDecimal("92233720368547.7580")
+	if _, err = conn.Execute(sqlInsert, money, NewDecimal(555, 2), "12.1"); err != nil {
+		t.Fatalf("Error executing insert: %s", err)
+	}
+
+	var cursor *Cursor
+	if cursor, err = conn.Execute(sqlSelect); err != nil {
+		t.Fatalf("Unexpected error in select: %s", err)
+	}
+	defer cursor.Close()
+
+	if !cursor.Next() {
+		t.Fatalf("Error in fetch: %s", cursor.Err())
+	}
+	vals := cursor.Row()
+	st.Equal("92233720368547.7580", vals[0].(Decimal).String())
+	st.Equal("5.55", vals[1].(Decimal).String())
+	st.Equal("12.1", vals[2].(Decimal).String())
+
+	var d Decimal
+	var r big.Rat
+	var s string
+	if err = cursor.Scan(&d, &r, &s); err != nil {
+		t.Fatalf("Unexpected error in scan: %s", err)
+	}
+	st.Equal(0, money.Rat().Cmp(d.Rat()))
+	st.Equal("111/20", r.String())
+	st.Equal("12.1", s)
+}
+
 func TestInsertBlob(t *testing.T) {
 	st := SuperTest{t}
 	os.Remove(TestFilename)
@@ -622,3 +671,151 @@ func TestInsertBlob(t *testing.T) {
 		st.Equal(memo, string(vals[3].([]byte)))
 	}
 }
+
+func TestInsertFirebird4Types(t *testing.T) {
+	st := SuperTest{t}
+	os.Remove(TestFilename)
+
+	conn, err := Create(TestConnectionString + "exact_decimals=true;")
+	if err != nil {
+		t.Fatalf("Unexpected error creating database: %s", err)
+	}
+	defer conn.Drop()
+
+	sqlSchema := `CREATE TABLE TEST (B BOOLEAN, I INT128, N NUMERIC(38,4), D16 DECFLOAT(16), D34 DECFLOAT(34),
+		TS TIMESTAMP WITH TIME ZONE, TM TIME WITH TIME ZONE);`
+	sqlInsert := "INSERT INTO TEST VALUES (?, ?, ?, ?, ?, ?, ?);"
+	sqlSelect := "SELECT * FROM TEST;"
+
+	if _, err = conn.Execute(sqlSchema); err != nil {
+		t.Fatalf("Error executing schema: %s", err)
+	}
+
+	huge, _ := new(big.Int).SetString("-170141183460469231731687303715884105728", 10)
+	numeric, _ := ParseDecimal("1234567890123456789012345678901234.5678")
+	d34, _ := ParseDecimal("1.234567890123456789012345678901234")
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Skipf("time zone database not available: %s", err)
+	}
+	ts := time.Date(2021, 6, 15, 10, 30, 0, 0, tokyo)
+	tm := time.Date(2020, 1, 1, 8, 15, 0, 0, time.FixedZone("+05:30", 330*60))
+	if _, err = conn.Execute(sqlInsert, true, huge, numeric, "-12.5", d34, ts, tm); err != nil {
+		t.Fatalf("Error executing insert: %s", err)
+	}
+
+	var cursor *Cursor
+	if cursor, err = conn.Execute(sqlSelect); err != nil {
+		t.Fatalf("Unexpected error in select: %s", err)
+	}
+	defer cursor.Close()
+
+	st.Equal("BOOLEAN", cursor.Columns[0].SqlType)
+	st.Equal("INT128", cursor.Columns[1].SqlType)
+	st.Equal("NUMERIC", cursor.Columns[2].SqlType)
+	st.Equal("DECFLOAT(16)", cursor.Columns[3].SqlType)
+	st.Equal("DECFLOAT(34)", cursor.Columns[4].SqlType)
+	st.Equal("TIMESTAMP WITH TIME ZONE", cursor.Columns[5].SqlType)
+	st.Equal("TIME WITH TIME ZONE", cursor.Columns[6].SqlType)
+
+	if !cursor.Next() {
+		t.Fatalf("Error in fetch: %s", cursor.Err())
+	}
+	vals := cursor.Row()
+	st.Equal(true, vals[0])
+	st.Equal(0, huge.Cmp(vals[1].(*big.Int)))
+	st.Equal(numeric.String(), vals[2].(Decimal).String())
+	st.Equal("-12.5", vals[3].(Decimal).String())
+	st.Equal(0, d34.Rat().Cmp(vals[4].(Decimal).Rat()))
+	st.True(ts.Equal(vals[5].(time.Time)))
+	st.Equal("Asia/Tokyo", vals[5].(time.Time).Location().String())
+	st.True(tm.Equal(vals[6].(time.Time)))
+	st.Equal("+05:30", vals[6].(time.Time).Location().String())
+
+	var b bool
+	var i big.Int
+	var rest interface{}
+	if err = cursor.Scan(&b, &i, &rest, &rest, &rest, &rest, &rest); err != nil {
+		t.Fatalf("Unexpected error in scan: %s", err)
+	}
+	st.True(b)
+	st.Equal(huge.String(), i.String())
+}
+
+func TestTimeZonesInTransaction(t *testing.T) {
+	st := SuperTest{t}
+	os.Remove(TestFilename)
+
+	conn, err := Create(TestConnectionString)
+	if err != nil {
+		t.Fatalf("Unexpected error creating database: %s", err)
+	}
+	defer conn.Drop()
+
+	if _, err = conn.Execute("CREATE TABLE TEST (TS TIMESTAMP WITH TIME ZONE)"); err != nil {
+		t.Fatalf("Error executing schema: %s", err)
+	}
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Skipf("time zone database not available: %s", err)
+	}
+	ts := time.Date(2021, 6, 15, 10, 30, 0, 0, tokyo)
+
+	// the zone names are first needed inside an explicit transaction
+	tx, err := conn.Begin(nil)
+	if err != nil {
+		t.Fatalf("Unexpected error starting transaction: %s", err)
+	}
+	_, err = tx.Execute("INSERT INTO TEST VALUES (?)", ts)
+	st.Nil(err)
+	st.False(conn.TransactionStarted())
+	row, err := tx.QueryRow("SELECT TS FROM TEST")
+	st.Nil(err)
+	st.Equal("Asia/Tokyo", row[0].(time.Time).Location().String())
+	st.False(conn.TransactionStarted())
+	st.Nil(tx.Rollback())
+
+	row, err = conn.QueryRow("SELECT COUNT(*) FROM TEST")
+	st.Nil(err)
+	st.Equal(int32(0), row[0])
+}
+
+func TestInsertArray(t *testing.T) {
+	st := SuperTest{t}
+	os.Remove(TestFilename)
+
+	conn, err := Create(TestConnectionString)
+	if err != nil {
+		t.Fatalf("Unexpected error creating database: %s", err)
+	}
+	defer conn.Drop()
+
+	sqlSchema := "CREATE TABLE TEST (ID INTEGER, INTS INTEGER[1:4], NAMES VARCHAR(10)[0:1], GRID SMALLINT[2, 3]);"
+	sqlInsert := "INSERT INTO TEST (ID, INTS, NAMES, GRID) VALUES (?, ?, ?, ?);"
+	sqlSelect := "SELECT INTS, NAMES, GRID FROM TEST WHERE ID = 1;"
+
+	if _, err = conn.Execute(sqlSchema); err != nil {
+		t.Fatalf("Error executing schema: %s", err)
+	}
+	grid := [][]int{{1, 2, 3}, {4, 5, 6}}
+	if _, err = conn.Execute(sqlInsert, 1, []int32{10, 20, 30, 40}, []string{"one", "two"}, grid); err != nil {
+		t.Fatalf("Error executing insert: %s", err)
+	}
+	if _, err = conn.Execute(sqlInsert, 2, []int{1, 2}, nil, nil); err == nil {
+		t.Fatalf("Expected error inserting array with wrong bounds")
+	}
+
+	row, err := conn.QueryRow(sqlSelect)
+	if err != nil {
+		t.Fatalf("Unexpected error in select: %s", err)
+	}
+	st.True(reflect.DeepEqual([]int32{10, 20, 30, 40}, row[0]))
+	st.True(reflect.DeepEqual([]string{"one", "two"}, row[1]))
+	st.True(reflect.DeepEqual([][]int16{{1, 2, 3}, {4, 5, 6}}, row[2]))
+
+	cols, err := conn.Columns("TEST")
+	if err != nil {
+		t.Fatalf("Unexpected error in columns: %s", err)
+	}
+	st.Equal("INTEGER", cols[1].SqlType)
+}
@@ -0,0 +1,190 @@
+package fb
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"strings"
+)
+
+// decFloatFormat describes an IEEE 754 decimal interchange format using the
+// densely packed decimal coefficient encoding, as used by DECFLOAT.
+type decFloatFormat struct {
+	bits    uint
+	expBits uint
+	declets int
+	bias    int
+	digits  int
+}
+
+var (
+	decFloat16 = decFloatFormat{64, 8, 5, 398, 16}
+	decFloat34 = decFloatFormat{128, 12, 11, 6176, 34}
+)
+
+var errDecFloatOverflow = errors.New("decfloat overflow")
+
+var two128 = new(big.Int).Lsh(big.NewInt(1), 128)
+
+func bitsOf(w *big.Int, pos, n uint) uint64 {
+	mask := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), n), big.NewInt(1))
+	return new(big.Int).And(new(big.Int).Rsh(w, pos), mask).Uint64()
+}
+
+func wordsToBig(lo, hi uint64) *big.Int {
+	w := new(big.Int).SetUint64(hi)
+	w.Lsh(w, 64)
+	return w.Or(w, new(big.Int).SetUint64(lo))
+}
+
+func bigToWords(w *big.Int) (lo, hi uint64) {
+	return bitsOf(w, 0, 64), bitsOf(w, 64, 64)
+}
+
+func bigIntFromInt128(lo, hi uint64) *big.Int {
+	v := wordsToBig(lo, hi)
+	if hi>>63 != 0 {
+		v.Sub(v, two128)
+	}
+	return v
+}
+
+func int128FromBigInt(v *big.Int) (lo, hi uint64, err error) {
+	if v.BitLen() > 127 && !(v.Sign() < 0 && v.BitLen() == 128 && v.TrailingZeroBits() == 127) {
+		return 0, 0, errors.New("int128 overflow")
+	}
+	w := new(big.Int).Set(v)
+	if w.Sign() < 0 {
+		w.Add(w, two128)
+	}
+	lo, hi = bigToWords(w)
+	return
+}
+
+// decodeDecFloat returns the value of w. Infinities and NaN have no Decimal
+// representation and are returned as special with finite set to false.
+func decodeDecFloat(f decFloatFormat, w *big.Int) (d Decimal, special float64, finite bool) {
+	negative := bitsOf(w, f.bits-1, 1) == 1
+	g := bitsOf(w, f.bits-6, 5)
+	if g>>1 == 0xF {
+		if g&1 == 1 {
+			return Decimal{}, math.NaN(), false
+		}
+		if negative {
+			return Decimal{}, math.Inf(-1), false
+		}
+		return Decimal{}, math.Inf(1), false
+	}
+	var expMsb, msd uint64
+	if g>>3 != 3 {
+		expMsb, msd = g>>3, g&7
+	} else {
+		expMsb, msd = (g>>1)&3, 8+g&1
+	}
+	exp := int(expMsb<<f.expBits|bitsOf(w, f.bits-6-f.expBits, f.expBits)) - f.bias
+	coefficient := big.NewInt(int64(msd))
+	thousand := big.NewInt(1000)
+	for i := f.declets - 1; i >= 0; i-- {
+		coefficient.Mul(coefficient, thousand)
+		coefficient.Add(coefficient, big.NewInt(int64(dpdDecode(bitsOf(w, uint(i*10), 10)))))
+	}
+	if negative {
+		coefficient.Neg(coefficient)
+	}
+	return Decimal{coefficient, -exp}, 0, true
+}
+
+func encodeDecFloat(f decFloatFormat, d Decimal) (w *big.Int, err error) {
+	if n := len(new(big.Int).Abs(d.unscaled()).String()); n > f.digits {
+		d = d.Rescale(d.Scale - (n - f.digits))
+		if len(new(big.Int).Abs(d.unscaled()).String()) > f.digits {
+			d = d.Rescale(d.Scale - 1)
+		}
+	}
+	if biased := f.bias - d.Scale; biased < 0 {
+		d = d.Rescale(f.bias)
+	}
+	biased := uint64(f.bias - d.Scale)
+	if biased >= 3<<f.expBits {
+		return nil, errDecFloatOverflow
+	}
+	u := d.unscaled()
+	digits := new(big.Int).Abs(u).String()
+	digits = strings.Repeat("0", f.digits-len(digits)) + digits
+
+	w = new(big.Int)
+	if u.Sign() < 0 {
+		w.SetBit(w, int(f.bits-1), 1)
+	}
+	msd := uint64(digits[0] - '0')
+	top2 := biased >> f.expBits
+	var g uint64
+	if msd < 8 {
+		g = top2<<3 | msd
+	} else {
+		g = 0x18 | top2<<1 | (msd - 8)
+	}
+	w.Or(w, new(big.Int).Lsh(new(big.Int).SetUint64(g), f.bits-6))
+	cont := biased & (1<<f.expBits - 1)
+	w.Or(w, new(big.Int).Lsh(new(big.Int).SetUint64(cont), f.bits-6-f.expBits))
+	for i := 0; i < f.declets; i++ {
+		group := digits[1+3*i : 4+3*i]
+		n := int(group[0]-'0')*100 + int(group[1]-'0')*10 + int(group[2]-'0')
+		shift := uint((f.declets - 1 - i) * 10)
+		w.Or(w, new(big.Int).Lsh(new(big.Int).SetUint64(dpdEncode(n)), shift))
+	}
+	return w, nil
+}
+
+// dpdDecode converts a 10 bit densely packed declet to its value 0-999.
+func dpdDecode(declet uint64) int {
+	b := func(i uint) int { return int(declet>>i) & 1 }
+	var d2, d1, d0 int
+	if b(3) == 0 {
+		d2, d1, d0 = int(declet>>7), int(declet>>4)&7, int(declet)&7
+	} else {
+		switch declet >> 1 & 3 {
+		case 0:
+			d2, d1, d0 = int(declet>>7), int(declet>>4)&7, 8+b(0)
+		case 1:
+			d2, d1, d0 = int(declet>>7), 8+b(4), b(6)<<2|b(5)<<1|b(0)
+		case 2:
+			d2, d1, d0 = 8+b(7), int(declet>>4)&7, b(9)<<2|b(8)<<1|b(0)
+		case 3:
+			switch declet >> 5 & 3 {
+			case 0:
+				d2, d1, d0 = 8+b(7), 8+b(4), b(9)<<2|b(8)<<1|b(0)
+			case 1:
+				d2, d1, d0 = 8+b(7), b(9)<<2|b(8)<<1|b(4), 8+b(0)
+			case 2:
+				d2, d1, d0 = int(declet>>7), 8+b(4), 8+b(0)
+			case 3:
+				d2, d1, d0 = 8+b(7), 8+b(4), 8+b(0)
+			}
+		}
+	}
+	return d2*100 + d1*10 + d0
+}
+
+// dpdEncode converts a value 0-999 to a 10 bit densely packed declet.
+func dpdEncode(n int) uint64 {
+	d2, d1, d0 := uint64(n/100), uint64(n/10%10), uint64(n%10)
+	large2, large1, large0 := d2 >= 8, d1 >= 8, d0 >= 8
+	switch {
+	case !large2 && !large1 && !large0:
+		return d2<<7 | d1<<4 | d0
+	case !large2 && !large1 && large0:
+		return d2<<7 | d1<<4 | 0x8 | d0&1
+	case !large2 && large1 && !large0:
+		return d2<<7 | (d0>>1)<<5 | (d1&1)<<4 | 0xA | d0&1
+	case large2 && !large1 && !large0:
+		return (d0>>1)<<8 | (d2&1)<<7 | d1<<4 | 0xC | d0&1
+	case large2 && large1 && !large0:
+		return (d0>>1)<<8 | (d2&1)<<7 | (d1&1)<<4 | 0xE | d0&1
+	case large2 && !large1 && large0:
+		return (d1>>1)<<8 | (d2&1)<<7 | 0x20 | (d1&1)<<4 | 0xE | d0&1
+	case !large2 && large1 && large0:
+		return d2<<7 | 0x40 | (d1&1)<<4 | 0xE | d0&1
+	}
+	return (d2&1)<<7 | 0x60 | (d1&1)<<4 | 0xE | d0&1
+}
@@ -0,0 +1,116 @@
+package fb
+
+import (
+	"math"
+	"math/big"
+	"testing"
+)
+
+func TestDpdRoundTrip(t *testing.T) {
+	for n := 0; n < 1000; n++ {
+		declet := dpdEncode(n)
+		if declet >= 1<<10 {
+			t.Fatalf("dpdEncode(%d) = %#x does not fit in 10 bits", n, declet)
+		}
+		if got := dpdDecode(declet); got != n {
+			t.Errorf("dpdDecode(dpdEncode(%d)) = %d", n, got)
+		}
+	}
+}
+
+func TestDecFloatKnownValues(t *testing.T) {
+	tests := []struct {
+		f      decFloatFormat
+		lo, hi uint64
+		value  string
+	}{
+		{decFloat16, 0x2238000000000000, 0, "0"},
+		{decFloat16, 0x2238000000000001, 0, "1"},
+		{decFloat16, 0xA238000000000001, 0, "-1"},
+		{decFloat16, 0x2234000000000015, 0, "1.5"},
+		{decFloat34, 0x0000000000000001, 0x2208000000000000, "1"},
+		{decFloat34, 0x0000000000000015, 0xA207C00000000000, "-1.5"},
+	}
+	for _, test := range tests {
+		w := wordsToBig(test.lo, test.hi)
+		d, _, finite := decodeDecFloat(test.f, w)
+		if !finite || d.String() != test.value {
+			t.Errorf("decodeDecFloat(%#x): expected %s, got %s", w, test.value, d)
+		}
+		expected, _ := ParseDecimal(test.value)
+		enc, err := encodeDecFloat(test.f, expected)
+		if err != nil {
+			t.Errorf("encodeDecFloat(%s) failed: %v", test.value, err)
+			continue
+		}
+		if enc.Cmp(w) != 0 {
+			t.Errorf("encodeDecFloat(%s): expected %#x, got %#x", test.value, w, enc)
+		}
+	}
+}
+
+func TestDecFloatRoundTrip(t *testing.T) {
+	for _, s := range []string{"0", "123.456", "-98765432109876.54", "9999999999999999", "0.000000000000000000000001", "1234567890123456789012345678901234"} {
+		for _, f := range []decFloatFormat{decFloat16, decFloat34} {
+			in, _ := ParseDecimal(s)
+			if len(in.Unscaled.String()) > f.digits {
+				continue
+			}
+			w, err := encodeDecFloat(f, in)
+			if err != nil {
+				t.Errorf("encodeDecFloat(%s) failed: %v", s, err)
+				continue
+			}
+			out, _, _ := decodeDecFloat(f, w)
+			if out.Rat().Cmp(in.Rat()) != 0 {
+				t.Errorf("decfloat round trip of %s gave %s", s, out)
+			}
+		}
+	}
+
+	rounded, _ := encodeDecFloat(decFloat16, NewDecimal(12345678901234567, 0))
+	d, _, _ := decodeDecFloat(decFloat16, rounded)
+	if d.String() != "12345678901234570" {
+		t.Errorf("expected rounding to 16 digits, got %s", d)
+	}
+
+	if _, err := encodeDecFloat(decFloat16, Decimal{big.NewInt(1), -400}); err != errDecFloatOverflow {
+		t.Errorf("expected overflow, got %v", err)
+	}
+}
+
+func TestDecFloatSpecialValues(t *testing.T) {
+	if _, special, finite := decodeDecFloat(decFloat16, wordsToBig(0x7800000000000000, 0)); finite || !math.IsInf(special, 1) {
+		t.Errorf("expected +Inf, got %v", special)
+	}
+	if _, special, finite := decodeDecFloat(decFloat34, wordsToBig(0, 0xF800000000000000)); finite || !math.IsInf(special, -1) {
+		t.Errorf("expected -Inf, got %v", special)
+	}
+	if _, special, finite := decodeDecFloat(decFloat16, wordsToBig(0x7C00000000000000, 0)); finite || !math.IsNaN(special) {
+		t.Errorf("expected NaN, got %v", special)
+	}
+}
+
+func TestInt128RoundTrip(t *testing.T) {
+	max := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 127), big.NewInt(1))
+	min := new(big.Int).Neg(new(big.Int).Lsh(big.NewInt(1), 127))
+	for _, v := range []*big.Int{big.NewInt(0), big.NewInt(-1), big.NewInt(1 << 62), max, min} {
+		lo, hi, err := int128FromBigInt(v)
+		if err != nil {
+			t.Errorf("int128FromBigInt(%s) failed: %v", v, err)
+			continue
+		}
+		if got := bigIntFromInt128(lo, hi); got.Cmp(v) != 0 {
+			t.Errorf("int128 round trip of %s gave %s", v, got)
+		}
+	}
+	if lo, hi, _ := int128FromBigInt(big.NewInt(-1)); lo != math.MaxUint64 || hi != math.MaxUint64 {
+		t.Errorf("expected two's complement -1, got %#x %#x", hi, lo)
+	}
+	if _, _, err := int128FromBigInt(new(big.Int).Add(max, big.NewInt(1))); err == nil {
+		t.Errorf("expected overflow above max int128")
+	}
+	if _, _, err := int128FromBigInt(new(big.Int).Sub(min, big.NewInt(1))); err == nil {
+		t.Errorf("expected overflow below min int128")
+	}
+}
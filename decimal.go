@@ -0,0 +1,128 @@
+package fb
+
+import (
+	"database/sql/driver"
+	"errors"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Decimal is an exact fixed-point number: Unscaled / 10^Scale.
+type Decimal struct {
+	Unscaled *big.Int
+	Scale    int
+}
+
+var errInvalidDecimal = errors.New("invalid decimal value")
+
+var bigTen = big.NewInt(10)
+
+func NewDecimal(unscaled int64, scale int) Decimal {
+	return Decimal{big.NewInt(unscaled), scale}
+}
+
+func ParseDecimal(s string) (d Decimal, err error) {
+	s = strings.TrimSpace(s)
+	exp := 0
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		if exp, err = strconv.Atoi(s[i+1:]); err != nil {
+			return Decimal{}, errInvalidDecimal
+		}
+		s = s[:i]
+	}
+	digits := s
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		digits = s[:i] + s[i+1:]
+		d.Scale = len(s) - i - 1
+	}
+	d.Scale -= exp
+	if digits == "" || digits == "-" || digits == "+" {
+		return Decimal{}, errInvalidDecimal
+	}
+	for i, c := range digits {
+		if (c < '0' || c > '9') && !(i == 0 && (c == '-' || c == '+')) {
+			return Decimal{}, errInvalidDecimal
+		}
+	}
+	d.Unscaled = new(big.Int)
+	if _, ok := d.Unscaled.SetString(digits, 10); !ok {
+		return Decimal{}, errInvalidDecimal
+	}
+	if d.Scale < 0 {
+		d = d.Rescale(0)
+	}
+	return d, nil
+}
+
+func (d Decimal) unscaled() *big.Int {
+	if d.Unscaled == nil {
+		return new(big.Int)
+	}
+	return d.Unscaled
+}
+
+func (d Decimal) Float64() float64 {
+	f, _ := d.Rat().Float64()
+	return f
+}
+
+func (d Decimal) Rat() *big.Rat {
+	r := new(big.Rat).SetInt(d.unscaled())
+	if d.Scale > 0 {
+		r.Quo(r, new(big.Rat).SetInt(pow10(d.Scale)))
+	} else if d.Scale < 0 {
+		r.Mul(r, new(big.Rat).SetInt(pow10(-d.Scale)))
+	}
+	return r
+}
+
+// Rescale returns d with the given number of fractional digits, rounding
+// half away from zero when digits are dropped.
+func (d Decimal) Rescale(scale int) Decimal {
+	u := new(big.Int).Set(d.unscaled())
+	if scale >= d.Scale {
+		u.Mul(u, pow10(scale-d.Scale))
+		return Decimal{u, scale}
+	}
+	divisor := pow10(d.Scale - scale)
+	q, r := u.QuoRem(u, divisor, new(big.Int))
+	if r.Abs(r).Lsh(r, 1).Cmp(divisor) >= 0 {
+		if d.unscaled().Sign() < 0 {
+			q.Sub(q, big.NewInt(1))
+		} else {
+			q.Add(q, big.NewInt(1))
+		}
+	}
+	return Decimal{q, scale}
+}
+
+func (d *Decimal) Scan(value interface{}) (err error) {
+	*d, err = decimalFromIf(value)
+	return
+}
+
+func (d Decimal) String() string {
+	if d.Scale <= 0 {
+		return d.Rescale(0).unscaled().String()
+	}
+	u := d.unscaled()
+	digits := new(big.Int).Abs(u).String()
+	if len(digits) <= d.Scale {
+		digits = strings.Repeat("0", d.Scale-len(digits)+1) + digits
+	}
+	point := len(digits) - d.Scale
+	s := digits[:point] + "." + digits[point:]
+	if u.Sign() < 0 {
+		s = "-" + s
+	}
+	return s
+}
+
+func (d Decimal) Value() (driver.Value, error) {
+	return d.String(), nil
+}
+
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(bigTen, big.NewInt(int64(n)), nil)
+}
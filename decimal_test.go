@@ -0,0 +1,76 @@
+package fb
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestParseDecimal(t *testing.T) {
+	tests := []struct {
+		in       string
+		unscaled string
+		scale    int
+		out      string
+	}{
+		{"0", "0", 0, "0"},
+		{"12.1", "121", 1, "12.1"},
+		{"-0.05", "-5", 2, "-0.05"},
+		{"+.5", "5", 1, "0.5"},
+		{"12345.1234", "123451234", 4, "12345.1234"},
+		{"1.5e3", "1500", 0, "1500"},
+		{"1.5E-3", "15", 4, "0.0015"},
+		{"123456789012345678901234567890.12", "12345678901234567890123456789012", 2, "123456789012345678901234567890.12"},
+	}
+	for _, test := range tests {
+		d, err := ParseDecimal(test.in)
+		if err != nil {
+			t.Errorf("ParseDecimal(%q) failed: %v", test.in, err)
+			continue
+		}
+		if d.Unscaled.String() != test.unscaled || d.Scale != test.scale {
+			t.Errorf("ParseDecimal(%q): expected %s scale %d, got %s scale %d", test.in, test.unscaled, test.scale, d.Unscaled, d.Scale)
+		}
+		if d.String() != test.out {
+			t.Errorf("ParseDecimal(%q).String(): expected %s, got %s", test.in, test.out, d.String())
+		}
+	}
+
+	for _, bad := range []string{"", "-", "abc", "1.2.3", "1e", "1-2"} {
+		if _, err := ParseDecimal(bad); err == nil {
+			t.Errorf("ParseDecimal(%q) should fail", bad)
+		}
+	}
+}
+
+func TestDecimalRescale(t *testing.T) {
+	st := SuperTest{t}
+	st.Equal("1.2300", NewDecimal(123, 2).Rescale(4).String())
+	st.Equal("1.24", NewDecimal(12350, 4).Rescale(2).String())
+	st.Equal("-1.24", NewDecimal(-12350, 4).Rescale(2).String())
+	st.Equal("1.23", NewDecimal(12349, 4).Rescale(2).String())
+	st.Equal("0", Decimal{}.String())
+}
+
+func TestDecimalRat(t *testing.T) {
+	st := SuperTest{t}
+	st.Equal(0, NewDecimal(555, 2).Rat().Cmp(big.NewRat(111, 20)))
+	st.Equal(5.55, NewDecimal(555, 2).Float64())
+}
+
+func TestDecimalScan(t *testing.T) {
+	st := SuperTest{t}
+	var d Decimal
+	if err := ConvertValue(&d, "12345.1234"); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	st.Equal("12345.1234", d.String())
+	if err := ConvertValue(&d, 5.55); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	st.Equal("5.55", d.String())
+	var s string
+	if err := ConvertValue(&s, NewDecimal(-5, 3)); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	st.Equal("-0.005", s)
+}
@@ -0,0 +1,162 @@
+package fb
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"math/big"
+)
+
+const DriverName = "firebirdsql"
+
+func init() {
+	sql.Register(DriverName, &Driver{})
+}
+
+type Driver struct{}
+
+func (d *Driver) Open(name string) (driver.Conn, error) {
+	conn, err := Connect(name)
+	if err != nil {
+		return nil, err
+	}
+	return &driverConn{conn}, nil
+}
+
+type driverConn struct {
+	conn *Connection
+}
+
+func (dc *driverConn) Prepare(query string) (driver.Stmt, error) {
+	stmt, err := dc.conn.Prepare(query)
+	if err != nil {
+		return nil, err
+	}
+	return &driverStmt{stmt}, nil
+}
+
+func (dc *driverConn) Close() error {
+	return dc.conn.Close()
+}
+
+func (dc *driverConn) Begin() (driver.Tx, error) {
+	if err := dc.conn.TransactionStart(""); err != nil {
+		return nil, err
+	}
+	return &driverTx{dc.conn}, nil
+}
+
+type driverTx struct {
+	conn *Connection
+}
+
+func (tx *driverTx) Commit() error {
+	return tx.conn.Commit()
+}
+
+func (tx *driverTx) Rollback() error {
+	return tx.conn.Rollback()
+}
+
+type driverStmt struct {
+	stmt *Statement
+}
+
+func (ds *driverStmt) Close() error {
+	return ds.stmt.Close()
+}
+
+func (ds *driverStmt) NumInput() int {
+	return len(ds.stmt.Params)
+}
+
+func (ds *driverStmt) Exec(args []driver.Value) (driver.Result, error) {
+	cursor, err := ds.stmt.Execute(argsFromValues(args)...)
+	if err != nil {
+		return nil, err
+	}
+	if cursor != nil {
+		if err = cursor.Close(); err != nil {
+			return nil, err
+		}
+	}
+	return driverResult(ds.stmt.connection.RowsAffected()), nil
+}
+
+func (ds *driverStmt) Query(args []driver.Value) (driver.Rows, error) {
+	cursor, err := ds.stmt.Execute(argsFromValues(args)...)
+	if err != nil {
+		return nil, err
+	}
+	return &driverRows{cursor}, nil
+}
+
+type driverResult int64
+
+func (r driverResult) LastInsertId() (int64, error) {
+	return 0, errors.New("fb: LastInsertId is not supported; use a generator or RETURNING")
+}
+
+func (r driverResult) RowsAffected() (int64, error) {
+	return int64(r), nil
+}
+
+type driverRows struct {
+	cursor *Cursor
+}
+
+func (dr *driverRows) Columns() []string {
+	if dr.cursor == nil {
+		return []string{}
+	}
+	names := make([]string, len(dr.cursor.Columns))
+	for i, col := range dr.cursor.Columns {
+		names[i] = col.Name
+	}
+	return names
+}
+
+func (dr *driverRows) Close() error {
+	if dr.cursor == nil || !dr.cursor.open {
+		return nil
+	}
+	return dr.cursor.Close()
+}
+
+func (dr *driverRows) Next(dest []driver.Value) error {
+	if dr.cursor == nil {
+		return io.EOF
+	}
+	if !dr.cursor.Next() {
+		return dr.cursor.Err()
+	}
+	for i, v := range dr.cursor.row[:len(dest)] {
+		dest[i] = driverValueFromIf(v)
+	}
+	return nil
+}
+
+func argsFromValues(values []driver.Value) []interface{} {
+	args := make([]interface{}, len(values))
+	for i, v := range values {
+		args[i] = v
+	}
+	return args
+}
+
+func driverValueFromIf(v interface{}) driver.Value {
+	switch v := v.(type) {
+	case int16:
+		return int64(v)
+	case int32:
+		return int64(v)
+	case float32:
+		return float64(v)
+	case Decimal:
+		return v.String()
+	case *big.Int:
+		return v.String()
+	}
+	return v
+}
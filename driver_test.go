@@ -0,0 +1,117 @@
+package fb
+
+import (
+	"database/sql"
+	"os"
+	"testing"
+)
+
+func TestDriverOpen(t *testing.T) {
+	os.Remove(TestFilename)
+
+	conn, err := Create(TestConnectionString)
+	if err != nil {
+		t.Fatalf("Unexpected error creating database: %s", err)
+	}
+	defer conn.Drop()
+
+	db, err := sql.Open(DriverName, TestConnectionString)
+	if err != nil {
+		t.Fatalf("Unexpected error opening database: %s", err)
+	}
+	defer db.Close()
+	if err = db.Ping(); err != nil {
+		t.Fatalf("Unexpected error pinging database: %s", err)
+	}
+}
+
+func TestDriverExecQuery(t *testing.T) {
+	st := SuperTest{t}
+	os.Remove(TestFilename)
+
+	conn, err := Create(TestConnectionString)
+	if err != nil {
+		t.Fatalf("Unexpected error creating database: %s", err)
+	}
+	defer conn.Drop()
+	if _, err = conn.Execute("CREATE TABLE TEST (ID INT, NAME VARCHAR(20))"); err != nil {
+		t.Fatalf("Unexpected error creating table: %s", err)
+	}
+
+	db, err := sql.Open(DriverName, TestConnectionString)
+	if err != nil {
+		t.Fatalf("Unexpected error opening database: %s", err)
+	}
+	defer db.Close()
+
+	result, err := db.Exec("INSERT INTO TEST (ID, NAME) VALUES (?, ?)", 1, "Dave")
+	if err != nil {
+		t.Fatalf("Unexpected error inserting: %s", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		t.Fatalf("Unexpected error getting rows affected: %s", err)
+	}
+	st.Equal(int64(1), affected)
+
+	rows, err := db.Query("SELECT ID, NAME FROM TEST")
+	if err != nil {
+		t.Fatalf("Unexpected error querying: %s", err)
+	}
+	defer rows.Close()
+	cols, err := rows.Columns()
+	if err != nil {
+		t.Fatalf("Unexpected error getting columns: %s", err)
+	}
+	st.MustEqual(2, len(cols))
+	st.Equal("ID", cols[0])
+	st.Equal("NAME", cols[1])
+	st.MustEqual(true, rows.Next())
+	var id int
+	var name string
+	if err = rows.Scan(&id, &name); err != nil {
+		t.Fatalf("Unexpected error scanning: %s", err)
+	}
+	st.Equal(1, id)
+	st.Equal("Dave", name)
+	st.Equal(false, rows.Next())
+	st.Nil(rows.Err())
+}
+
+func TestDriverTransaction(t *testing.T) {
+	os.Remove(TestFilename)
+
+	conn, err := Create(TestConnectionString)
+	if err != nil {
+		t.Fatalf("Unexpected error creating database: %s", err)
+	}
+	defer conn.Drop()
+	if _, err = conn.Execute("CREATE TABLE TEST (ID INT)"); err != nil {
+		t.Fatalf("Unexpected error creating table: %s", err)
+	}
+
+	db, err := sql.Open(DriverName, TestConnectionString)
+	if err != nil {
+		t.Fatalf("Unexpected error opening database: %s", err)
+	}
+	defer db.Close()
+
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatalf("Unexpected error starting transaction: %s", err)
+	}
+	if _, err = tx.Exec("INSERT INTO TEST (ID) VALUES (?)", 1); err != nil {
+		t.Fatalf("Unexpected error inserting: %s", err)
+	}
+	if err = tx.Rollback(); err != nil {
+		t.Fatalf("Unexpected error rolling back: %s", err)
+	}
+
+	var count int
+	if err = db.QueryRow("SELECT COUNT(*) FROM TEST").Scan(&count); err != nil {
+		t.Fatalf("Unexpected error counting rows: %s", err)
+	}
+	if count != 0 {
+		t.Errorf("Expected 0 rows after rollback, found %d", count)
+	}
+}
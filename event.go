@@ -0,0 +1,259 @@
+package fb
+
+/*
+#include <stdint.h>
+#include <stdlib.h>
+#include <string.h>
+#include <ibase.h>
+#include "fb.h"
+*/
+import "C"
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+	"unsafe"
+)
+
+// isc_event_block accepts at most 15 names, so larger subscriptions are
+// split into several event blocks.
+const maxEventsPerBlock = 15
+
+// subscribeTimeout bounds the wait for the server's first notification in
+// Subscribe.
+const subscribeTimeout = 30 * time.Second
+
+type Event struct {
+	Name  string
+	Count int
+}
+
+type Subscription struct {
+	Events     <-chan Event
+	events     chan Event
+	connection *Connection
+	batches    []*eventBatch
+	done       chan struct{}
+	wg         sync.WaitGroup
+	closeOnce  sync.Once
+	mu         sync.Mutex
+	err        error
+}
+
+type eventBatch struct {
+	sub      *Subscription
+	key      uintptr
+	names    []string
+	length   C.short
+	buffer   *C.ISC_UCHAR
+	result   *C.ISC_UCHAR
+	signal   chan struct{}
+	mu       sync.Mutex
+	eventId  C.ISC_LONG
+	queued   bool
+	canceled bool
+}
+
+var eventRegistry = struct {
+	sync.Mutex
+	next    uintptr
+	batches map[uintptr]*eventBatch
+}{batches: make(map[uintptr]*eventBatch)}
+
+//export goEventCallback
+func goEventCallback(key C.uintptr_t, length C.ISC_USHORT, updated *C.ISC_UCHAR) {
+	eventRegistry.Lock()
+	defer eventRegistry.Unlock()
+	b := eventRegistry.batches[uintptr(key)]
+	if b == nil {
+		return
+	}
+	if length > 0 && updated != nil {
+		if C.short(length) > b.length {
+			length = C.ISC_USHORT(b.length)
+		}
+		C.memcpy(unsafe.Pointer(b.result), unsafe.Pointer(updated), C.size_t(length))
+	}
+	select {
+	case b.signal <- struct{}{}:
+	default:
+	}
+}
+
+func (conn *Connection) Subscribe(names ...string) (sub *Subscription, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), subscribeTimeout)
+	defer cancel()
+	return conn.SubscribeContext(ctx, names...)
+}
+
+// SubscribeContext registers interest in the named events. ctx bounds the
+// wait for the server to report the current counts; once subscribed, events
+// are delivered until Unsubscribe.
+func (conn *Connection) SubscribeContext(ctx context.Context, names ...string) (sub *Subscription, err error) {
+	if err = ctx.Err(); err != nil {
+		return
+	}
+	if err = conn.check(); err != nil {
+		return
+	}
+	if len(names) == 0 {
+		return nil, errors.New("no event names given")
+	}
+	for _, name := range names {
+		if len(name) == 0 || len(name) > 255 {
+			return nil, errors.New("invalid event name: " + name)
+		}
+	}
+	events := make(chan Event, 16)
+	sub = &Subscription{Events: events, events: events, connection: conn, done: make(chan struct{})}
+	for start := 0; start < len(names); start += maxEventsPerBlock {
+		end := start + maxEventsPerBlock
+		if end > len(names) {
+			end = len(names)
+		}
+		b := newEventBatch(sub, names[start:end])
+		sub.batches = append(sub.batches, b)
+		// the first notification only reports the current counts
+		if err = b.queue(); err == nil {
+			select {
+			case <-b.signal:
+				b.counts()
+				err = b.queue()
+			case <-ctx.Done():
+				err = ctx.Err()
+			}
+		}
+		if err != nil {
+			sub.Unsubscribe()
+			return nil, err
+		}
+	}
+	for _, b := range sub.batches {
+		sub.wg.Add(1)
+		go b.run()
+	}
+	return
+}
+
+func newEventBatch(sub *Subscription, names []string) *eventBatch {
+	epb := []byte{C.EPB_version1}
+	for _, name := range names {
+		epb = append(epb, byte(len(name)))
+		epb = append(epb, name...)
+		epb = append(epb, 0, 0, 0, 0)
+	}
+	b := &eventBatch{sub: sub, names: names, length: C.short(len(epb)), signal: make(chan struct{}, 1)}
+	b.buffer = (*C.ISC_UCHAR)(C.CBytes(epb))
+	b.result = (*C.ISC_UCHAR)(C.CBytes(epb))
+
+	eventRegistry.Lock()
+	eventRegistry.next++
+	b.key = eventRegistry.next
+	eventRegistry.batches[b.key] = b
+	eventRegistry.Unlock()
+	return b
+}
+
+func (b *eventBatch) queue() error {
+	var isc_status [20]C.ISC_STATUS
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.canceled {
+		return nil
+	}
+	C.fb_que_events(&isc_status[0], &b.sub.connection.db, &b.eventId, b.length, b.buffer, C.uintptr_t(b.key))
+	if err := fbErrorCheck(&isc_status); err != nil {
+		return err
+	}
+	b.queued = true
+	return nil
+}
+
+func (b *eventBatch) counts() []Event {
+	var counts [20]C.ISC_ULONG
+
+	eventRegistry.Lock()
+	C.isc_event_counts(&counts[0], b.length, b.buffer, b.result)
+	eventRegistry.Unlock()
+	var events []Event
+	for i, name := range b.names {
+		if counts[i] > 0 {
+			events = append(events, Event{Name: name, Count: int(counts[i])})
+		}
+	}
+	return events
+}
+
+func (b *eventBatch) run() {
+	defer b.sub.wg.Done()
+	for {
+		select {
+		case <-b.sub.done:
+			return
+		case <-b.signal:
+		}
+		b.mu.Lock()
+		b.queued = false
+		b.mu.Unlock()
+		for _, event := range b.counts() {
+			select {
+			case b.sub.events <- event:
+			case <-b.sub.done:
+				return
+			}
+		}
+		if err := b.queue(); err != nil {
+			b.sub.mu.Lock()
+			b.sub.err = err
+			b.sub.mu.Unlock()
+			go b.sub.Unsubscribe()
+			return
+		}
+	}
+}
+
+func (b *eventBatch) cancel() (err error) {
+	var isc_status [20]C.ISC_STATUS
+
+	b.mu.Lock()
+	b.canceled = true
+	if b.queued && b.sub.connection.db != 0 {
+		C.isc_cancel_events(&isc_status[0], &b.sub.connection.db, &b.eventId)
+		err = fbErrorCheck(&isc_status)
+		b.queued = false
+	}
+	b.mu.Unlock()
+
+	eventRegistry.Lock()
+	delete(eventRegistry.batches, b.key)
+	eventRegistry.Unlock()
+	return
+}
+
+// Err returns the error that ended the subscription, if any.
+func (sub *Subscription) Err() error {
+	sub.mu.Lock()
+	defer sub.mu.Unlock()
+	return sub.err
+}
+
+func (sub *Subscription) Unsubscribe() (err error) {
+	sub.closeOnce.Do(func() {
+		for _, b := range sub.batches {
+			if cerr := b.cancel(); cerr != nil && err == nil {
+				err = cerr
+			}
+		}
+		close(sub.done)
+		sub.wg.Wait()
+		for _, b := range sub.batches {
+			C.free(unsafe.Pointer(b.buffer))
+			C.free(unsafe.Pointer(b.result))
+		}
+		close(sub.events)
+	})
+	return
+}
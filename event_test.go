@@ -0,0 +1,67 @@
+package fb
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestSubscribe(t *testing.T) {
+	st := SuperTest{t}
+	os.Remove(TestFilename)
+
+	conn, err := Create(TestConnectionString)
+	if err != nil {
+		t.Fatalf("Unexpected error creating database: %s", err)
+	}
+	defer conn.Drop()
+
+	// more names than fit in one event block
+	var names []string
+	for i := 0; i < 20; i++ {
+		names = append(names, fmt.Sprintf("EVENT_%d", i))
+	}
+	sub, err := conn.Subscribe(names...)
+	if err != nil {
+		t.Fatalf("Unexpected error subscribing: %s", err)
+	}
+	st.Equal(2, len(sub.batches))
+
+	other, err := Connect(TestConnectionString)
+	if err != nil {
+		t.Fatalf("Unexpected error connecting: %s", err)
+	}
+	defer other.Close()
+	post := `EXECUTE BLOCK AS BEGIN
+		POST_EVENT 'EVENT_1'; POST_EVENT 'EVENT_17';
+	END`
+	if _, err = other.Execute(post); err != nil {
+		t.Fatalf("Unexpected error posting events: %s", err)
+	}
+
+	counts := make(map[string]int)
+	timeout := time.After(10 * time.Second)
+	for len(counts) < 2 {
+		select {
+		case event := <-sub.Events:
+			counts[event.Name] += event.Count
+		case <-timeout:
+			t.Fatalf("Timed out waiting for events, got %v", counts)
+		}
+	}
+	st.Equal(1, counts["EVENT_1"])
+	st.Equal(1, counts["EVENT_17"])
+
+	st.Nil(sub.Unsubscribe())
+	_, open := <-sub.Events
+	st.False(open)
+	st.Nil(sub.Unsubscribe())
+	st.Nil(sub.Err())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = conn.SubscribeContext(ctx, "EVENT_1")
+	st.Equal(context.Canceled, err)
+}
@@ -360,6 +360,8 @@ long calculate_buffsize(XSQLDA *sqlda)
 		} else if (dtp == SQL_VARYING) {
 			length += sizeof(short);
 			alignment = sizeof(short);
+		} else if (dtp == SQL_TIMESTAMP_TZ || dtp == SQL_TIMESTAMP_TZ_EX) {
+			alignment = sizeof(ISC_TIME);
 		}
 
 		offset = FB_ALIGN(offset, alignment);
@@ -394,3 +396,14 @@ char * fb_error_msg(const ISC_STATUS *isc_status)
 	}
 	return result;
 }
+
+static void event_callback(void *arg, ISC_USHORT length, const ISC_UCHAR *updated)
+{
+	goEventCallback((uintptr_t)arg, length, (ISC_UCHAR*)updated);
+}
+
+ISC_STATUS fb_que_events(ISC_STATUS *isc_status, isc_db_handle *db, ISC_LONG *event_id,
+	short length, const ISC_UCHAR *event_buffer, uintptr_t arg)
+{
+	return isc_que_events(isc_status, db, event_id, length, event_buffer, event_callback, (void*)arg);
+}
@@ -1,11 +1,57 @@
 #ifndef FB_H
 #define FB_H
 
+#include <stdint.h>
 #include <ibase.h>
 
 #define	FB_ALIGN(n, b)	((n + b - 1) & ~(b - 1))
 #define SHORT_SIZE sizeof(short)
 
+/* Firebird 3 and 4 data types, for building against older client headers */
+#ifndef SQL_BOOLEAN
+#define SQL_BOOLEAN 32764
+#define blr_bool 23
+#endif
+
+#ifndef SQL_TIMESTAMP_TZ
+#define SQL_TIMESTAMP_TZ_EX 32748
+#define SQL_TIME_TZ_EX 32750
+#define SQL_INT128 32752
+#define SQL_TIMESTAMP_TZ 32754
+#define SQL_TIME_TZ 32756
+#define SQL_DEC16 32760
+#define SQL_DEC34 32762
+#define blr_dec64 24
+#define blr_dec128 25
+#define blr_int128 26
+#define blr_sql_time_tz 28
+#define blr_timestamp_tz 29
+#define blr_ex_time_tz 30
+#define blr_ex_timestamp_tz 31
+
+typedef struct { ISC_TIME utc_time; ISC_USHORT time_zone; } ISC_TIME_TZ;
+typedef struct { ISC_TIME utc_time; ISC_USHORT time_zone; ISC_SHORT ext_offset; } ISC_TIME_TZ_EX;
+typedef struct { ISC_TIMESTAMP utc_timestamp; ISC_USHORT time_zone; } ISC_TIMESTAMP_TZ;
+typedef struct { ISC_TIMESTAMP utc_timestamp; ISC_USHORT time_zone; ISC_SHORT ext_offset; } ISC_TIMESTAMP_TZ_EX;
+typedef struct { ISC_UINT64 fb_data[1]; } FB_DEC16;
+typedef struct { ISC_UINT64 fb_data[2]; } FB_DEC34;
+typedef struct { ISC_UINT64 fb_data[2]; } FB_I128;
+#endif
+
+#ifndef isc_tpb_read_consistency
+#define isc_tpb_read_consistency 22
+#endif
+
+/* Firebird 3 online validation */
+#ifndef isc_action_svc_validate
+#define isc_action_svc_validate 26
+#define isc_spb_val_tab_incl 1
+#define isc_spb_val_tab_excl 2
+#define isc_spb_val_idx_incl 3
+#define isc_spb_val_idx_excl 4
+#define isc_spb_val_lock_timeout 5
+#endif
+
 char* trans_parseopts(char *opt, long *tpb_len);
 XSQLDA* sqlda_alloc(long cols);
 long calculate_buffsize(XSQLDA *sqlda);
@@ -20,4 +66,7 @@ typedef struct
 
 char * fb_error_msg(const ISC_STATUS *isc_status);
 
+ISC_STATUS fb_que_events(ISC_STATUS *isc_status, isc_db_handle *db, ISC_LONG *event_id,
+	short length, const ISC_UCHAR *event_buffer, uintptr_t arg);
+
 #endif
@@ -187,6 +187,25 @@ func sqlTypeFromCode(code, subType int) string {
 		case 2:
 			return "DECIMAL"
 		}
+	case C.SQL_BOOLEAN, C.blr_bool:
+		return "BOOLEAN"
+	case C.SQL_INT128, C.blr_int128:
+		switch subType {
+		case 0:
+			return "INT128"
+		case 1:
+			return "NUMERIC"
+		case 2:
+			return "DECIMAL"
+		}
+	case C.SQL_DEC16, C.blr_dec64:
+		return "DECFLOAT(16)"
+	case C.SQL_DEC34, C.blr_dec128:
+		return "DECFLOAT(34)"
+	case C.SQL_TIME_TZ, C.SQL_TIME_TZ_EX, C.blr_sql_time_tz, C.blr_ex_time_tz:
+		return "TIME WITH TIME ZONE"
+	case C.SQL_TIMESTAMP_TZ, C.SQL_TIMESTAMP_TZ_EX, C.blr_timestamp_tz, C.blr_ex_timestamp_tz:
+		return "TIMESTAMP WITH TIME ZONE"
 	}
 	return fmt.Sprintf("UNKNOWN %d, %d", code, subType)
 }
@@ -230,6 +249,19 @@ func precisionFromSqlvar(sqlvar *C.XSQLVAR) int16 {
 			return 18
 		}
 		break
+	case C.SQL_INT128:
+		switch sqlvar.sqlsubtype {
+		case 0:
+			return 0
+		case 1:
+			return 38
+		case 2:
+			return 38
+		}
+	case C.SQL_DEC16:
+		return 16
+	case C.SQL_DEC34:
+		return 34
 	}
 	return -1
 }
@@ -0,0 +1,205 @@
+package fb
+
+/*
+#include <ibase.h>
+#include <stdlib.h>
+*/
+import "C"
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+type InfoItem byte
+
+const (
+	InfoReads             InfoItem = C.isc_info_reads
+	InfoWrites            InfoItem = C.isc_info_writes
+	InfoFetches           InfoItem = C.isc_info_fetches
+	InfoMarks             InfoItem = C.isc_info_marks
+	InfoIscVersion        InfoItem = C.isc_info_isc_version
+	InfoPageSize          InfoItem = C.isc_info_page_size
+	InfoNumBuffers        InfoItem = C.isc_info_num_buffers
+	InfoCurrentMemory     InfoItem = C.isc_info_current_memory
+	InfoMaxMemory         InfoItem = C.isc_info_max_memory
+	InfoAttachmentID      InfoItem = C.isc_info_attachment_id
+	InfoSweepInterval     InfoItem = C.isc_info_sweep_interval
+	InfoODSVersion        InfoItem = C.isc_info_ods_version
+	InfoODSMinorVersion   InfoItem = C.isc_info_ods_minor_version
+	InfoForcedWrites      InfoItem = C.isc_info_forced_writes
+	InfoDialect           InfoItem = C.isc_info_db_sql_dialect
+	InfoReadOnly          InfoItem = C.isc_info_db_read_only
+	InfoSizeInPages       InfoItem = C.isc_info_db_size_in_pages
+	InfoFirebirdVersion   InfoItem = C.isc_info_firebird_version
+	InfoOldestTransaction InfoItem = C.isc_info_oldest_transaction
+	InfoOldestActive      InfoItem = C.isc_info_oldest_active
+	InfoOldestSnapshot    InfoItem = C.isc_info_oldest_snapshot
+	InfoNextTransaction   InfoItem = C.isc_info_next_transaction
+	InfoActiveTranCount   InfoItem = C.isc_info_active_tran_count
+)
+
+const maxInfoBufferSize = 0x7FFF
+
+type DatabaseInfo struct {
+	PageSize           int
+	ODSVersion         string
+	ServerVersion      string
+	AttachmentID       int64
+	CurrentMemory      int64
+	MaxMemory          int64
+	PageReads          int64
+	PageWrites         int64
+	PageFetches        int64
+	PageMarks          int64
+	OldestTransaction  int64
+	OldestActive       int64
+	OldestSnapshot     int64
+	NextTransaction    int64
+	ActiveTransactions int64
+	SweepInterval      int
+	Buffers            int
+	SizeInPages        int64
+	Dialect            int
+	ReadOnly           bool
+	ForcedWrites       bool
+}
+
+// InfoItems requests arbitrary isc_database_info items and returns the raw
+// value of each item the server answered.
+func (conn *Connection) InfoItems(items ...InfoItem) (values map[InfoItem][]byte, err error) {
+	var isc_status [20]C.ISC_STATUS
+
+	if err = conn.check(); err != nil {
+		return
+	}
+	request := make([]byte, len(items)+1)
+	for i, item := range items {
+		request[i] = byte(item)
+	}
+	request[len(items)] = C.isc_info_end
+	crequest := C.CBytes(request)
+	defer C.free(crequest)
+
+	for size := 1024; ; size *= 2 {
+		if size > maxInfoBufferSize {
+			size = maxInfoBufferSize
+		}
+		cresult := C.malloc(C.size_t(size))
+		C.isc_database_info(&isc_status[0], &conn.db, C.short(len(request)), (*C.ISC_SCHAR)(crequest),
+			C.short(size), (*C.ISC_SCHAR)(cresult))
+		result := C.GoBytes(cresult, C.int(size))
+		C.free(cresult)
+		if err = fbErrorCheck(&isc_status); err != nil {
+			return
+		}
+		var truncated bool
+		if values, truncated, err = parseInfo(result); err != nil || !truncated {
+			return
+		}
+		if size == maxInfoBufferSize {
+			return nil, errors.New("database info does not fit in the result buffer")
+		}
+	}
+}
+
+func parseInfo(b []byte) (values map[InfoItem][]byte, truncated bool, err error) {
+	values = make(map[InfoItem][]byte)
+	for i := 0; i < len(b); {
+		item := b[i]
+		i++
+		switch item {
+		case C.isc_info_end:
+			return
+		case C.isc_info_truncated:
+			return values, true, nil
+		}
+		if i+2 > len(b) {
+			return nil, false, errors.New("malformed database info")
+		}
+		n := int(binary.LittleEndian.Uint16(b[i:]))
+		i += 2
+		if i+n > len(b) {
+			return nil, false, errors.New("malformed database info")
+		}
+		if item != C.isc_info_error {
+			values[InfoItem(item)] = b[i : i+n]
+		}
+		i += n
+	}
+	return
+}
+
+// infoInt decodes a little endian integer of any length, as isc_vax_integer does.
+func infoInt(b []byte) (i int64) {
+	for n := len(b) - 1; n >= 0; n-- {
+		i = i<<8 | int64(b[n])
+	}
+	if len(b) > 0 && len(b) < 8 && b[len(b)-1]&0x80 != 0 {
+		i -= 1 << (8 * uint(len(b)))
+	}
+	return
+}
+
+// infoStrings decodes a counted list of length prefixed strings.
+func infoStrings(b []byte) (s []string) {
+	if len(b) == 0 {
+		return
+	}
+	for i, count := 1, int(b[0]); count > 0 && i < len(b); count-- {
+		n := int(b[i])
+		i++
+		if i+n > len(b) {
+			break
+		}
+		s = append(s, string(b[i:i+n]))
+		i += n
+	}
+	return
+}
+
+// Info returns the commonly monitored properties of the attached database.
+func (conn *Connection) Info() (info *DatabaseInfo, err error) {
+	var values map[InfoItem][]byte
+	values, err = conn.InfoItems(
+		InfoPageSize, InfoODSVersion, InfoODSMinorVersion, InfoIscVersion, InfoFirebirdVersion,
+		InfoAttachmentID, InfoCurrentMemory, InfoMaxMemory,
+		InfoReads, InfoWrites, InfoFetches, InfoMarks,
+		InfoOldestTransaction, InfoOldestActive, InfoOldestSnapshot, InfoNextTransaction, InfoActiveTranCount,
+		InfoSweepInterval, InfoNumBuffers, InfoSizeInPages, InfoDialect, InfoReadOnly, InfoForcedWrites)
+	if err != nil {
+		return
+	}
+	info = &DatabaseInfo{
+		PageSize:           int(infoInt(values[InfoPageSize])),
+		ODSVersion:         fmt.Sprintf("%d.%d", infoInt(values[InfoODSVersion]), infoInt(values[InfoODSMinorVersion])),
+		AttachmentID:       infoInt(values[InfoAttachmentID]),
+		CurrentMemory:      infoInt(values[InfoCurrentMemory]),
+		MaxMemory:          infoInt(values[InfoMaxMemory]),
+		PageReads:          infoInt(values[InfoReads]),
+		PageWrites:         infoInt(values[InfoWrites]),
+		PageFetches:        infoInt(values[InfoFetches]),
+		PageMarks:          infoInt(values[InfoMarks]),
+		OldestTransaction:  infoInt(values[InfoOldestTransaction]),
+		OldestActive:       infoInt(values[InfoOldestActive]),
+		OldestSnapshot:     infoInt(values[InfoOldestSnapshot]),
+		NextTransaction:    infoInt(values[InfoNextTransaction]),
+		ActiveTransactions: infoInt(values[InfoActiveTranCount]),
+		SweepInterval:      int(infoInt(values[InfoSweepInterval])),
+		Buffers:            int(infoInt(values[InfoNumBuffers])),
+		SizeInPages:        infoInt(values[InfoSizeInPages]),
+		Dialect:            int(infoInt(values[InfoDialect])),
+		ReadOnly:           infoInt(values[InfoReadOnly]) != 0,
+		ForcedWrites:       infoInt(values[InfoForcedWrites]) != 0,
+	}
+	// Firebird version strings are more useful than the InterBase compatible ones
+	versions := infoStrings(values[InfoFirebirdVersion])
+	if len(versions) == 0 {
+		versions = infoStrings(values[InfoIscVersion])
+	}
+	if len(versions) > 0 {
+		info.ServerVersion = versions[0]
+	}
+	return
+}
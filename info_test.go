@@ -0,0 +1,65 @@
+package fb
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseInfo(t *testing.T) {
+	st := SuperTest{t}
+
+	b := []byte{
+		14, 2, 0, 0x00, 0x10, // page size 4096
+		103, 10, 0, 1, 8, 'L', 'I', '-', 'V', '4', '.', '0', '0', // firebird version
+		3, 0, 0, // error item is skipped
+		1, 0xFF, 0xFF, // nothing is read after the end marker
+	}
+	values, truncated, err := parseInfo(b)
+	st.Nil(err)
+	st.False(truncated)
+	st.Equal(2, len(values))
+	st.Equal(int64(4096), infoInt(values[InfoPageSize]))
+	st.True(reflect.DeepEqual([]string{"LI-V4.00"}, infoStrings(values[InfoFirebirdVersion])))
+
+	_, truncated, err = parseInfo([]byte{14, 2, 0, 0x00, 0x10, 2})
+	st.Nil(err)
+	st.True(truncated)
+
+	_, _, err = parseInfo([]byte{14, 4, 0, 1})
+	st.True(err != nil)
+
+	st.Equal(int64(-1), infoInt([]byte{0xFF, 0xFF, 0xFF, 0xFF}))
+	st.Equal(int64(0x01020304), infoInt([]byte{4, 3, 2, 1}))
+	st.Equal(int64(0), infoInt(nil))
+}
+
+func TestConnectionInfo(t *testing.T) {
+	st := SuperTest{t}
+	os.Remove(TestFilename)
+
+	conn, err := Create(TestConnectionString)
+	if err != nil {
+		t.Fatalf("Unexpected error creating database: %s", err)
+	}
+	defer conn.Drop()
+
+	info, err := conn.Info()
+	if err != nil {
+		t.Fatalf("Unexpected error in Info: %s", err)
+	}
+	st.True(info.PageSize >= 1024)
+	st.True(strings.Contains(info.ODSVersion, "."))
+	st.True(info.ServerVersion != "")
+	st.True(info.AttachmentID > 0)
+	st.True(info.CurrentMemory > 0)
+	st.True(info.NextTransaction >= info.OldestTransaction)
+	st.Equal(3, info.Dialect)
+	st.False(info.ReadOnly)
+
+	values, err := conn.InfoItems(InfoPageSize, InfoDialect)
+	st.Nil(err)
+	st.Equal(int64(info.PageSize), infoInt(values[InfoPageSize]))
+	st.Equal(int64(3), infoInt(values[InfoDialect]))
+}
@@ -0,0 +1,195 @@
+package fb
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode/utf8"
+)
+
+func isNameStart(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// parseNamedParams rewrites :name and @name parameters to positional ones and
+// returns the names in order. String literals, quoted identifiers, comments
+// and the body of EXECUTE BLOCK are left alone, since colons there refer to
+// PSQL variables.
+func parseNamedParams(sql string) (rewritten string, names []string, err error) {
+	s := &scriptScanner{src: sql, line: 1, column: 1}
+	var out bytes.Buffer
+	last, depth := 0, 0
+	positional, block := false, false
+	var prevWord string
+scan:
+	for s.pos < len(s.src) {
+		c := s.src[s.pos]
+		switch {
+		case s.at("--"):
+			s.skipUntil("\n")
+		case s.at("/*"):
+			s.skip("/*")
+			if !s.skipUntil("*/") {
+				return "", nil, errors.New("unterminated comment in SQL")
+			}
+		case c == '\'' || c == '"':
+			s.next()
+			if !s.skipQuoted(rune(c)) {
+				return "", nil, errors.New("unterminated string in SQL")
+			}
+		case s.atAltQuote():
+			if !s.skipAltQuoted() {
+				return "", nil, errors.New("unterminated string in SQL")
+			}
+		case c == '?':
+			positional = true
+			s.next()
+		case (c == ':' || c == '@') && s.pos+1 < len(s.src) && isNameStart(s.src[s.pos+1]) && !scriptIdentChar(s.src, s.pos-1):
+			start := s.pos
+			s.next()
+			for s.pos < len(s.src) && scriptIdentChar(s.src, s.pos) {
+				s.next()
+			}
+			out.WriteString(sql[last:start])
+			out.WriteByte('?')
+			last = s.pos
+			names = append(names, sql[start+1:s.pos])
+		case isNameStart(c):
+			start := s.pos
+			for s.pos < len(s.src) && scriptIdentChar(s.src, s.pos) {
+				s.next()
+			}
+			word := strings.ToUpper(sql[start:s.pos])
+			if word == "BLOCK" && prevWord == "EXECUTE" {
+				block = true
+			} else if word == "AS" && block && depth == 0 {
+				break scan
+			}
+			prevWord = word
+		default:
+			switch c {
+			case '(':
+				depth++
+			case ')':
+				depth--
+			}
+			if r, _ := utf8.DecodeRuneInString(s.src[s.pos:]); r != ' ' && r != '\t' && r != '\n' && r != '\r' {
+				prevWord = ""
+			}
+			s.next()
+		}
+	}
+	if positional && len(names) > 0 {
+		return "", nil, errors.New("SQL mixes positional and named parameters")
+	}
+	out.WriteString(sql[last:])
+	return out.String(), names, nil
+}
+
+// namedArgs looks up the values of names in a map or a struct. Every key of
+// a map must be used; struct fields are matched like ScanStruct matches
+// columns and may go unused.
+func namedArgs(names []string, params interface{}) (args []interface{}, err error) {
+	args = make([]interface{}, len(names))
+	if m, ok := params.(map[string]interface{}); ok {
+		used := make(map[string]bool, len(m))
+		var folded map[string]string
+		for i, name := range names {
+			key := name
+			if _, ok := m[key]; !ok {
+				if folded == nil {
+					folded = make(map[string]string, len(m))
+					for k := range m {
+						folded[strings.ToUpper(k)] = k
+					}
+				}
+				if key, ok = folded[strings.ToUpper(name)]; !ok {
+					return nil, fmt.Errorf("missing named parameter :%s", name)
+				}
+			}
+			args[i] = m[key]
+			used[key] = true
+		}
+		for key := range m {
+			if !used[key] {
+				return nil, fmt.Errorf("unused named parameter %s", key)
+			}
+		}
+		return
+	}
+
+	v := reflect.ValueOf(params)
+	if v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("named parameters must be a map[string]interface{} or a struct, not %T", params)
+	}
+	if !v.CanAddr() {
+		addressable := reflect.New(v.Type()).Elem()
+		addressable.Set(v)
+		v = addressable
+	}
+	plan := structPlanFor(v.Type())
+	for i, name := range names {
+		path := plan.lookup(name)
+		if path == nil {
+			return nil, fmt.Errorf("missing named parameter :%s", name)
+		}
+		args[i] = fieldArg(v, path)
+	}
+	return
+}
+
+// fieldArg reads a field for binding; nil pointers on the way bind NULL.
+func fieldArg(v reflect.Value, path []int) interface{} {
+	for i, x := range path {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return nil
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
+		}
+		if _, ok := v.Interface().(Interfacer); !ok {
+			v = v.Elem()
+		}
+	} else if arg, ok := v.Addr().Interface().(Interfacer); ok {
+		return arg
+	}
+	return v.Interface()
+}
+
+// ExecuteNamed runs sql with :name or @name parameters bound from a
+// map[string]interface{} or a struct.
+func (conn *Connection) ExecuteNamed(sql string, params interface{}) (cursor *Cursor, err error) {
+	var args []interface{}
+	if sql, args, err = bindNamed(sql, params); err != nil {
+		return
+	}
+	return conn.Execute(sql, args...)
+}
+
+func (tx *Transaction) ExecuteNamed(sql string, params interface{}) (cursor *Cursor, err error) {
+	var args []interface{}
+	if sql, args, err = bindNamed(sql, params); err != nil {
+		return
+	}
+	return tx.Execute(sql, args...)
+}
+
+func bindNamed(sql string, params interface{}) (rewritten string, args []interface{}, err error) {
+	var names []string
+	if rewritten, names, err = parseNamedParams(sql); err != nil {
+		return
+	}
+	args, err = namedArgs(names, params)
+	return
+}
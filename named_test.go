@@ -0,0 +1,118 @@
+package fb
+
+import (
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestParseNamedParams(t *testing.T) {
+	st := SuperTest{t}
+
+	sql, names, err := parseNamedParams(`UPDATE T SET A = :a, B = @b_2 /* :c */ WHERE S = ':d' AND "E:x" = :A -- :f`)
+	st.Nil(err)
+	st.Equal(`UPDATE T SET A = ?, B = ? /* :c */ WHERE S = ':d' AND "E:x" = ? -- :f`, sql)
+	st.True(reflect.DeepEqual([]string{"a", "b_2", "A"}, names))
+
+	const block = `EXECUTE BLOCK (X INT = :x) RETURNS (Y INT) AS
+DECLARE Z INT;
+BEGIN
+  SELECT ID FROM T WHERE ID = :X INTO :Z;
+  Y = :Z; SUSPEND;
+END`
+	sql, names, err = parseNamedParams(block)
+	st.Nil(err)
+	st.True(reflect.DeepEqual([]string{"x"}, names))
+	st.Equal(block[:len("EXECUTE BLOCK (X INT = ")]+"?"+block[len("EXECUTE BLOCK (X INT = :x"):], sql)
+
+	sql, names, err = parseNamedParams("SELECT A[1:2], q'{:no}', x@y FROM T WHERE ID = ?")
+	st.Nil(err)
+	st.Equal("SELECT A[1:2], q'{:no}', x@y FROM T WHERE ID = ?", sql)
+	st.Equal(0, len(names))
+
+	_, _, err = parseNamedParams("SELECT 1 FROM T WHERE A = ? AND B = :b")
+	st.True(err != nil)
+	_, _, err = parseNamedParams("SELECT ':a FROM T")
+	st.True(err != nil)
+}
+
+type namedBase struct {
+	ID int64 `fb:"id"`
+}
+
+type namedParams struct {
+	*namedBase
+	Name   NullableString
+	Nick   *string
+	Unused int
+}
+
+func TestNamedArgs(t *testing.T) {
+	st := SuperTest{t}
+
+	args, err := namedArgs([]string{"a", "B", "a"}, map[string]interface{}{"a": 1, "b": "x"})
+	st.Nil(err)
+	st.True(reflect.DeepEqual([]interface{}{1, "x", 1}, args))
+
+	_, err = namedArgs([]string{"a"}, map[string]interface{}{"a": 1, "b": 2})
+	st.True(err != nil)
+	_, err = namedArgs([]string{"a", "c"}, map[string]interface{}{"a": 1})
+	st.True(err != nil)
+
+	nick := "n"
+	p := namedParams{namedBase: &namedBase{ID: 5}, Name: NullableString{Null: true}, Nick: &nick}
+	args, err = namedArgs([]string{"ID", "name", "nick"}, p)
+	st.Nil(err)
+	st.Equal(int64(5), args[0])
+	st.Equal(nil, args[1].(Interfacer).Interface())
+	st.Equal("n", args[2])
+
+	args, err = namedArgs([]string{"id", "nick"}, &namedParams{})
+	st.Nil(err)
+	st.Equal(nil, args[0])
+	st.Equal(nil, args[1])
+
+	_, err = namedArgs([]string{"missing"}, p)
+	st.True(err != nil)
+	_, err = namedArgs([]string{"a"}, 42)
+	st.True(err != nil)
+}
+
+func TestExecuteNamed(t *testing.T) {
+	st := SuperTest{t}
+	os.Remove(TestFilename)
+
+	conn, err := Create(TestConnectionString)
+	if err != nil {
+		t.Fatalf("Unexpected error creating database: %s", err)
+	}
+	defer conn.Drop()
+
+	if _, err = conn.Execute("CREATE TABLE TEST (ID INT, NAME VARCHAR(20))"); err != nil {
+		t.Fatalf("Error executing schema: %s", err)
+	}
+	_, err = conn.ExecuteNamed("INSERT INTO TEST (ID, NAME) VALUES (:id, :name)",
+		map[string]interface{}{"id": 1, "name": "one"})
+	st.Nil(err)
+	_, err = conn.ExecuteNamed("INSERT INTO TEST (ID, NAME) VALUES (@id, @name)",
+		struct {
+			ID   int
+			Name string
+		}{2, "two"})
+	st.Nil(err)
+
+	cursor, err := conn.ExecuteNamed("SELECT NAME FROM TEST WHERE ID = :id OR ID = :id + 1 ORDER BY ID",
+		map[string]interface{}{"id": 1})
+	if err != nil {
+		t.Fatalf("Unexpected error in ExecuteNamed: %s", err)
+	}
+	defer cursor.Close()
+	var names []string
+	for cursor.Next() {
+		names = append(names, cursor.Row()[0].(string))
+	}
+	st.True(reflect.DeepEqual([]string{"one", "two"}, names))
+
+	_, err = conn.ExecuteNamed("SELECT NAME FROM TEST WHERE ID = :id", map[string]interface{}{"id": 1, "extra": 2})
+	st.True(err != nil)
+}
@@ -0,0 +1,309 @@
+package fb
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+var ErrPoolClosed = errors.New("fb: pool closed")
+
+type PoolOptions struct {
+	MinOpen int
+	// MaxOpen limits the attachments, idle or in use; 0 means no limit.
+	MaxOpen     int
+	IdleTimeout time.Duration
+	MaxLifetime time.Duration
+	// HealthCheck runs on every checkout of an idle connection; by default
+	// the attachment id is requested from the server.
+	HealthCheck func(*Connection) error
+}
+
+type PoolStats struct {
+	Open  int
+	Idle  int
+	InUse int
+}
+
+type pooledConn struct {
+	conn     *Connection
+	created  time.Time
+	returned time.Time
+}
+
+// Pool shares attachments to one database. Connections taken with Get go
+// back with Put, which rolls back any transaction still open on them.
+type Pool struct {
+	db    *Database
+	opts  PoolOptions
+	mu    sync.Mutex
+	idle  []*pooledConn
+	inUse map[*Connection]*pooledConn
+	open  int
+	// wake is closed and replaced whenever a connection is returned or closed
+	wake   chan struct{}
+	closed bool
+	stop   chan struct{}
+}
+
+func NewPool(parms string, opts PoolOptions) (*Pool, error) {
+	db, err := New(parms)
+	if err != nil {
+		return nil, err
+	}
+	return db.NewPool(opts)
+}
+
+func (db *Database) NewPool(opts PoolOptions) (p *Pool, err error) {
+	if opts.MinOpen < 0 || opts.MaxOpen < 0 || opts.MaxOpen > 0 && opts.MinOpen > opts.MaxOpen {
+		return nil, errors.New("invalid pool size")
+	}
+	p = &Pool{
+		db:    db,
+		opts:  opts,
+		inUse: make(map[*Connection]*pooledConn),
+		wake:  make(chan struct{}),
+		stop:  make(chan struct{}),
+	}
+	if err = p.fill(); err != nil {
+		p.Close()
+		return nil, err
+	}
+	if interval := p.cleanInterval(); interval > 0 {
+		go p.clean(interval)
+	}
+	return p, nil
+}
+
+func (p *Pool) connect() (pc *pooledConn, err error) {
+	var conn *Connection
+	if conn, err = p.db.Connect(); err != nil {
+		return
+	}
+	return &pooledConn{conn: conn, created: time.Now()}, nil
+}
+
+// fill opens idle connections until MinOpen are open.
+func (p *Pool) fill() error {
+	for {
+		p.mu.Lock()
+		if p.closed || p.open >= p.opts.MinOpen {
+			p.mu.Unlock()
+			return nil
+		}
+		p.open++
+		p.mu.Unlock()
+		pc, err := p.connect()
+		p.mu.Lock()
+		if err != nil {
+			p.open--
+			p.mu.Unlock()
+			return err
+		}
+		pc.returned = pc.created
+		p.idle = append(p.idle, pc)
+		p.mu.Unlock()
+	}
+}
+
+func (p *Pool) expired(pc *pooledConn, now time.Time) bool {
+	return p.opts.MaxLifetime > 0 && now.Sub(pc.created) >= p.opts.MaxLifetime
+}
+
+func (p *Pool) healthy(pc *pooledConn) bool {
+	if p.expired(pc, time.Now()) {
+		return false
+	}
+	if p.opts.HealthCheck != nil {
+		return p.opts.HealthCheck(pc.conn) == nil
+	}
+	_, err := pc.conn.InfoItems(InfoAttachmentID)
+	return err == nil
+}
+
+// discard closes a connection that no longer counts as open. Closing commits,
+// so open transactions are rolled back first.
+func (p *Pool) discard(pc *pooledConn) {
+	pc.conn.rollbackTransactions()
+	pc.conn.Close()
+	p.mu.Lock()
+	p.open--
+	p.broadcast()
+	p.mu.Unlock()
+}
+
+func (p *Pool) broadcast() {
+	close(p.wake)
+	p.wake = make(chan struct{})
+}
+
+func (p *Pool) Get() (*Connection, error) {
+	return p.GetContext(context.Background())
+}
+
+// GetContext takes an idle connection that passes the health check or opens
+// a new one, waiting for one to be returned when MaxOpen is reached.
+func (p *Pool) GetContext(ctx context.Context) (*Connection, error) {
+	for {
+		p.mu.Lock()
+		if p.closed {
+			p.mu.Unlock()
+			return nil, ErrPoolClosed
+		}
+		if n := len(p.idle); n > 0 {
+			pc := p.idle[n-1]
+			p.idle = p.idle[:n-1]
+			p.mu.Unlock()
+			if !p.healthy(pc) {
+				p.discard(pc)
+				continue
+			}
+			p.mu.Lock()
+			p.inUse[pc.conn] = pc
+			p.mu.Unlock()
+			return pc.conn, nil
+		}
+		if p.opts.MaxOpen == 0 || p.open < p.opts.MaxOpen {
+			p.open++
+			p.mu.Unlock()
+			pc, err := p.connect()
+			p.mu.Lock()
+			if err != nil {
+				p.open--
+				p.broadcast()
+				p.mu.Unlock()
+				return nil, err
+			}
+			p.inUse[pc.conn] = pc
+			p.mu.Unlock()
+			return pc.conn, nil
+		}
+		wake := p.wake
+		p.mu.Unlock()
+		select {
+		case <-wake:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// Put returns a connection taken from the pool, rolling back its transaction
+// and any started with Begin.
+func (p *Pool) Put(conn *Connection) error {
+	p.mu.Lock()
+	pc, ok := p.inUse[conn]
+	if !ok {
+		p.mu.Unlock()
+		return errors.New("fb: connection does not belong to the pool")
+	}
+	delete(p.inUse, conn)
+	closed := p.closed
+	p.mu.Unlock()
+
+	if closed || p.expired(pc, time.Now()) || conn.check() != nil {
+		p.discard(pc)
+		return nil
+	}
+	if err := conn.rollbackTransactions(); err != nil {
+		p.discard(pc)
+		return err
+	}
+	pc.returned = time.Now()
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		p.discard(pc)
+		return nil
+	}
+	p.idle = append(p.idle, pc)
+	p.broadcast()
+	p.mu.Unlock()
+	return nil
+}
+
+func (p *Pool) Stats() PoolStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return PoolStats{Open: p.open, Idle: len(p.idle), InUse: len(p.inUse)}
+}
+
+func (p *Pool) cleanInterval() time.Duration {
+	interval := p.opts.IdleTimeout
+	if interval == 0 || p.opts.MaxLifetime > 0 && p.opts.MaxLifetime < interval {
+		interval = p.opts.MaxLifetime
+	}
+	if interval > 0 {
+		interval /= 2
+		if interval < time.Second {
+			interval = time.Second
+		}
+	}
+	return interval
+}
+
+func (p *Pool) clean(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-p.stop:
+			return
+		case <-ticker.C:
+			p.prune()
+			p.fill()
+		}
+	}
+}
+
+// prune closes idle connections past their lifetime, and those idle too long
+// while more than MinOpen are open.
+func (p *Pool) prune() {
+	now := time.Now()
+	var stale []*pooledConn
+	p.mu.Lock()
+	open := p.open
+	idle := p.idle[:0]
+	for _, pc := range p.idle {
+		idleTooLong := p.opts.IdleTimeout > 0 && now.Sub(pc.returned) >= p.opts.IdleTimeout
+		if p.expired(pc, now) || idleTooLong && open > p.opts.MinOpen {
+			stale = append(stale, pc)
+			open--
+		} else {
+			idle = append(idle, pc)
+		}
+	}
+	p.idle = idle
+	p.mu.Unlock()
+	for _, pc := range stale {
+		p.discard(pc)
+	}
+}
+
+// Close closes the idle connections; connections in use are closed when they
+// are returned.
+func (p *Pool) Close() error {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return nil
+	}
+	p.closed = true
+	close(p.stop)
+	idle := p.idle
+	p.idle = nil
+	p.broadcast()
+	p.mu.Unlock()
+	var err error
+	for _, pc := range idle {
+		pc.conn.rollbackTransactions()
+		if cerr := pc.conn.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+		p.mu.Lock()
+		p.open--
+		p.mu.Unlock()
+	}
+	return err
+}
@@ -0,0 +1,111 @@
+package fb
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestPool(t *testing.T) {
+	st := SuperTest{t}
+	os.Remove(TestFilename)
+
+	conn, err := Create(TestConnectionString)
+	if err != nil {
+		t.Fatalf("Unexpected error creating database: %s", err)
+	}
+	defer conn.Drop()
+	if _, err = conn.Execute("CREATE TABLE TEST (ID INT)"); err != nil {
+		t.Fatalf("Error executing schema: %s", err)
+	}
+
+	pool, err := NewPool(TestConnectionString, PoolOptions{MinOpen: 1, MaxOpen: 2})
+	if err != nil {
+		t.Fatalf("Unexpected error creating pool: %s", err)
+	}
+	defer pool.Close()
+	st.Equal(PoolStats{Open: 1, Idle: 1}, pool.Stats())
+
+	c1, err := pool.Get()
+	st.Nil(err)
+	c2, err := pool.Get()
+	st.Nil(err)
+	st.True(c1 != c2)
+	st.Equal(PoolStats{Open: 2, InUse: 2}, pool.Stats())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	_, err = pool.GetContext(ctx)
+	cancel()
+	st.Equal(context.DeadlineExceeded, err)
+
+	// an uncommitted insert is rolled back on return
+	st.Nil(c1.TransactionStart(""))
+	_, err = c1.Execute("INSERT INTO TEST (ID) VALUES (1)")
+	st.Nil(err)
+	st.Nil(pool.Put(c1))
+	st.True(pool.Put(c1) != nil)
+	st.Equal(PoolStats{Open: 2, Idle: 1, InUse: 1}, pool.Stats())
+
+	c3, err := pool.Get()
+	st.Nil(err)
+	st.True(c3 == c1)
+	st.False(c3.TransactionStarted())
+	row, err := c3.QueryRow("SELECT COUNT(*) FROM TEST")
+	st.Nil(err)
+	st.Equal(int32(0), row[0])
+	st.Nil(pool.Put(c3))
+
+	// so is work in a transaction from Begin
+	tx, err := c2.Begin(nil)
+	st.Nil(err)
+	_, err = tx.Execute("INSERT INTO TEST (ID) VALUES (2)")
+	st.Nil(err)
+	st.Nil(pool.Put(c2))
+	st.True(tx.Commit() != nil)
+
+	// and on connections closed because they expired
+	expiring, err := NewPool(TestConnectionString, PoolOptions{MaxLifetime: time.Nanosecond})
+	if err != nil {
+		t.Fatalf("Unexpected error creating pool: %s", err)
+	}
+	c5, err := expiring.Get()
+	st.Nil(err)
+	st.Nil(c5.TransactionStart(""))
+	_, err = c5.Execute("INSERT INTO TEST (ID) VALUES (3)")
+	st.Nil(err)
+	st.Nil(expiring.Put(c5))
+	st.Equal(PoolStats{}, expiring.Stats())
+	st.Nil(expiring.Close())
+
+	row, err = conn.QueryRow("SELECT COUNT(*) FROM TEST")
+	st.Nil(err)
+	st.Equal(int32(0), row[0])
+
+	checks := 0
+	failing, err := NewPool(TestConnectionString, PoolOptions{MinOpen: 1, HealthCheck: func(*Connection) error {
+		checks++
+		return errors.New("unhealthy")
+	}})
+	if err != nil {
+		t.Fatalf("Unexpected error creating pool: %s", err)
+	}
+	c4, err := failing.Get()
+	st.Nil(err)
+	st.Equal(1, checks)
+	st.Equal(PoolStats{Open: 1, InUse: 1}, failing.Stats())
+	st.Nil(failing.Close())
+	st.Nil(failing.Put(c4))
+	st.Equal(PoolStats{}, failing.Stats())
+	_, err = failing.Get()
+	st.Equal(ErrPoolClosed, err)
+}
+
+func TestPoolCleanInterval(t *testing.T) {
+	st := SuperTest{t}
+	st.Equal(time.Duration(0), (&Pool{}).cleanInterval())
+	st.Equal(30*time.Second, (&Pool{opts: PoolOptions{IdleTimeout: time.Minute}}).cleanInterval())
+	st.Equal(5*time.Second, (&Pool{opts: PoolOptions{IdleTimeout: time.Minute, MaxLifetime: 10 * time.Second}}).cleanInterval())
+	st.Equal(time.Second, (&Pool{opts: PoolOptions{MaxLifetime: time.Millisecond}}).cleanInterval())
+}
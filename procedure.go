@@ -0,0 +1,156 @@
+package fb
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+)
+
+type ProcedureParameter struct {
+	Column
+	Position int
+}
+
+type Procedure struct {
+	Name string
+	// Selectable procedures return rows with SUSPEND and are called with
+	// SELECT; executable ones return a single row from EXECUTE PROCEDURE.
+	Selectable bool
+	Inputs     []*ProcedureParameter
+	Outputs    []*ProcedureParameter
+	Source     string
+	storedName string
+}
+
+func (conn *Connection) Procedure(name string) (proc *Procedure, err error) {
+	const sqlProcedure = `
+		SELECT rdb$procedure_name, rdb$procedure_type, rdb$procedure_source
+		FROM rdb$procedures
+		WHERE rdb$procedure_name = ?`
+	const sqlParameters = `
+		SELECT p.rdb$parameter_name, p.rdb$parameter_type, p.rdb$parameter_number, p.rdb$field_source,
+			f.rdb$field_type, f.rdb$field_sub_type, f.rdb$field_length, f.rdb$field_precision, f.rdb$field_scale,
+			COALESCE(p.rdb$default_source, f.rdb$default_source) rdb$default_source,
+			COALESCE(p.rdb$null_flag, f.rdb$null_flag) rdb$null_flag
+		FROM rdb$procedure_parameters p
+		JOIN rdb$fields f ON p.rdb$field_source = f.rdb$field_name
+		WHERE p.rdb$procedure_name = ?
+		ORDER BY p.rdb$parameter_type, p.rdb$parameter_number`
+
+	name = conn.storedName(name)
+	var row Row
+	if row, err = conn.QueryRow(sqlProcedure, name); err != nil {
+		if err == io.EOF {
+			err = &Error{Message: "procedure " + name + " not found"}
+		}
+		return
+	}
+	var procName string
+	var procType NullableInt16
+	var source NullableString
+	if err = row.Scan(&procName, &procType, &source); err != nil {
+		return
+	}
+	proc = &Procedure{Name: conn.metaName(procName), Source: strings.TrimSpace(source.Value), storedName: strings.TrimRightFunc(procName, unicode.IsSpace)}
+	if procType.Null {
+		// databases from before Firebird 2.1 do not record the type
+		proc.Selectable = strings.Contains(strings.ToUpper(proc.Source), "SUSPEND")
+	} else {
+		proc.Selectable = procType.Value == 1
+	}
+
+	var cursor *Cursor
+	if cursor, err = conn.Execute(sqlParameters, name); err != nil {
+		return nil, err
+	}
+	defer cursor.Close()
+
+	for cursor.Next() {
+		var param ProcedureParameter
+		var paramType, sqlType int16
+		var nullFlag NullableInt16
+		if err = cursor.Scan(
+			&param.Name,
+			&paramType,
+			&param.Position,
+			&param.Domain,
+			&sqlType,
+			&param.SqlSubtype,
+			&param.Length,
+			&param.Precision,
+			&param.Scale,
+			&param.Default,
+			&nullFlag); err != nil {
+			return nil, err
+		}
+		conn.fixColumn(&param.Column, sqlType)
+		// rdb$null_flag is set for NOT NULL; Nullable matches cursor columns
+		param.Nullable = NullableBool{Value: nullFlag.Null || nullFlag.Value == 0}
+		if !param.Default.Null {
+			param.Default.Value = strings.TrimLeftFunc(strings.TrimPrefix(param.Default.Value, "="), unicode.IsSpace)
+		}
+		if paramType == 0 {
+			proc.Inputs = append(proc.Inputs, &param)
+		} else {
+			proc.Outputs = append(proc.Outputs, &param)
+		}
+	}
+	if cursor.Err() != io.EOF {
+		return nil, cursor.Err()
+	}
+	return
+}
+
+func (proc *Procedure) checkArgs(args []interface{}) error {
+	if len(args) > len(proc.Inputs) {
+		return fmt.Errorf("procedure %s takes at most %d arguments, %d given", proc.Name, len(proc.Inputs), len(args))
+	}
+	for i, param := range proc.Inputs {
+		if i >= len(args) {
+			// trailing parameters may be left out when they have defaults
+			if param.Default.Null {
+				return fmt.Errorf("procedure %s: missing argument %s", proc.Name, param.Name)
+			}
+			continue
+		}
+		arg := args[i]
+		if argi, ok := arg.(Interfacer); ok {
+			arg = argi.Interface()
+		}
+		if arg == nil && !param.Nullable.Value {
+			return fmt.Errorf("procedure %s: argument %s cannot be null", proc.Name, param.Name)
+		}
+	}
+	return nil
+}
+
+// CallProcedure checks args against the declared input parameters and runs
+// the procedure. Executable procedures return their output row, if they have
+// outputs; selectable ones return an open Cursor over their rows.
+func (conn *Connection) CallProcedure(name string, args ...interface{}) (row Row, cursor *Cursor, err error) {
+	var proc *Procedure
+	if proc, err = conn.Procedure(name); err != nil {
+		return
+	}
+	if err = proc.checkArgs(args); err != nil {
+		return
+	}
+	sql := quoteIdentifier(proc.storedName)
+	if len(args) > 0 {
+		sql += " (" + strings.TrimSuffix(strings.Repeat("?, ", len(args)), ", ") + ")"
+	}
+	if proc.Selectable {
+		cursor, err = conn.Execute("SELECT * FROM "+sql, args...)
+		return
+	}
+	var c *Cursor
+	if c, err = conn.Execute("EXECUTE PROCEDURE "+sql, args...); err != nil || c == nil {
+		return
+	}
+	row = c.output
+	if err = c.Close(); err != nil {
+		row = nil
+	}
+	return
+}
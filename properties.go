@@ -0,0 +1,105 @@
+package fb
+
+/*
+#include <ibase.h>
+*/
+import "C"
+
+import (
+	"errors"
+	"time"
+)
+
+type ShutdownMode byte
+
+const (
+	ShutdownMulti  ShutdownMode = C.isc_spb_prp_sm_multi
+	ShutdownSingle ShutdownMode = C.isc_spb_prp_sm_single
+	ShutdownFull   ShutdownMode = C.isc_spb_prp_sm_full
+)
+
+// ShutdownMethod decides what happens to connections still open when the
+// timeout expires.
+type ShutdownMethod byte
+
+const (
+	ShutdownForce            ShutdownMethod = C.isc_spb_prp_force_shutdown
+	ShutdownDenyAttachments  ShutdownMethod = C.isc_spb_prp_attachments_shutdown
+	ShutdownDenyTransactions ShutdownMethod = C.isc_spb_prp_transactions_shutdown
+)
+
+type WriteMode byte
+
+const (
+	WriteSync  WriteMode = C.isc_spb_prp_wm_sync
+	WriteAsync WriteMode = C.isc_spb_prp_wm_async
+)
+
+type AccessMode byte
+
+const (
+	AccessReadWrite AccessMode = C.isc_spb_prp_am_readwrite
+	AccessReadOnly  AccessMode = C.isc_spb_prp_am_readonly
+)
+
+func (svc *Service) properties(action, database string, build func(request *spb)) (err error) {
+	request := &spb{}
+	request.WriteByte(C.isc_action_svc_properties)
+	request.addString(C.isc_spb_dbname, database)
+	build(request)
+	_, err = svc.run(action, request, nil)
+	return
+}
+
+func (svc *Service) Shutdown(database string, mode ShutdownMode, timeout time.Duration) error {
+	return svc.ShutdownWithMethod(database, mode, ShutdownForce, timeout)
+}
+
+func (svc *Service) ShutdownWithMethod(database string, mode ShutdownMode, method ShutdownMethod, timeout time.Duration) error {
+	if timeout < 0 {
+		return errors.New("negative shutdown timeout")
+	}
+	return svc.properties("shutdown", database, func(request *spb) {
+		request.addByte(C.isc_spb_prp_shutdown_mode, byte(mode))
+		request.addInt(byte(method), uint32(timeout/time.Second))
+	})
+}
+
+func (svc *Service) BringOnline(database string) error {
+	return svc.properties("bring online", database, func(request *spb) {
+		request.addByte(C.isc_spb_prp_online_mode, C.isc_spb_prp_sm_normal)
+	})
+}
+
+func (svc *Service) SetAccessMode(database string, mode AccessMode) error {
+	return svc.properties("set access mode", database, func(request *spb) {
+		request.addByte(C.isc_spb_prp_access_mode, byte(mode))
+	})
+}
+
+func (svc *Service) SetDialect(database string, dialect int) error {
+	if dialect != 1 && dialect != 3 {
+		return errors.New("dialect must be 1 or 3")
+	}
+	return svc.properties("set dialect", database, func(request *spb) {
+		request.addInt(C.isc_spb_prp_set_sql_dialect, uint32(dialect))
+	})
+}
+
+func (svc *Service) SetPageBuffers(database string, buffers int) error {
+	return svc.properties("set page buffers", database, func(request *spb) {
+		request.addInt(C.isc_spb_prp_page_buffers, uint32(buffers))
+	})
+}
+
+func (svc *Service) SetSweepInterval(database string, interval int) error {
+	return svc.properties("set sweep interval", database, func(request *spb) {
+		request.addInt(C.isc_spb_prp_sweep_interval, uint32(interval))
+	})
+}
+
+func (svc *Service) SetWriteMode(database string, mode WriteMode) error {
+	return svc.properties("set write mode", database, func(request *spb) {
+		request.addByte(C.isc_spb_prp_write_mode, byte(mode))
+	})
+}
@@ -0,0 +1,70 @@
+package fb
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestServiceProperties(t *testing.T) {
+	st := SuperTest{t}
+	os.Remove(TestFilename)
+
+	conn, err := Create(TestConnectionString)
+	if err != nil {
+		t.Fatalf("Unexpected error creating database: %s", err)
+	}
+	conn.Close()
+	defer Drop(TestConnectionString)
+
+	svc, err := ConnectService(TestConnectionString)
+	if err != nil {
+		t.Fatalf("Unexpected error attaching to service manager: %s", err)
+	}
+	defer svc.Close()
+
+	header := func() HeaderStats {
+		stats, err := svc.DatabaseStats(TestFilename, StatsHeaderPages)
+		if err != nil {
+			t.Fatalf("Unexpected error in stats: %s", err)
+		}
+		return stats.Header
+	}
+	hasAttribute := func(h HeaderStats, attribute string) bool {
+		for _, a := range h.Attributes {
+			if strings.Contains(a, attribute) {
+				return true
+			}
+		}
+		return false
+	}
+
+	st.Nil(svc.SetSweepInterval(TestFilename, 5000))
+	st.Equal(5000, header().SweepInterval)
+
+	st.Nil(svc.SetWriteMode(TestFilename, WriteSync))
+	st.True(hasAttribute(header(), "force write"))
+	st.Nil(svc.SetWriteMode(TestFilename, WriteAsync))
+	st.False(hasAttribute(header(), "force write"))
+
+	st.Nil(svc.SetAccessMode(TestFilename, AccessReadOnly))
+	st.True(hasAttribute(header(), "read only"))
+	st.Nil(svc.SetAccessMode(TestFilename, AccessReadWrite))
+	st.False(hasAttribute(header(), "read only"))
+
+	st.Nil(svc.SetDialect(TestFilename, 3))
+	st.Equal(3, header().Dialect)
+	st.True(svc.SetDialect(TestFilename, 2) != nil)
+
+	st.Nil(svc.Shutdown(TestFilename, ShutdownFull, 0))
+	st.True(hasAttribute(header(), "shutdown"))
+	if conn, err = Connect(TestConnectionString); err == nil {
+		conn.Close()
+		t.Fatalf("Expected connect to fail while the database is shut down")
+	}
+	st.Nil(svc.BringOnline(TestFilename))
+	if conn, err = Connect(TestConnectionString); err != nil {
+		t.Fatalf("Unexpected error connecting after bringing online: %s", err)
+	}
+	conn.Close()
+}
@@ -0,0 +1,253 @@
+package fb
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type scriptStatement struct {
+	SQL    string
+	Number int
+	Line   int
+	Column int
+}
+
+// ScriptError reports where in a script a statement failed or could not be
+// parsed. Number is zero for parse errors.
+type ScriptError struct {
+	Number int
+	Line   int
+	Column int
+	SQL    string
+	Err    error
+}
+
+func (e *ScriptError) Error() string {
+	if e.Number == 0 {
+		return fmt.Sprintf("script line %d, column %d: %s", e.Line, e.Column, e.Err)
+	}
+	return fmt.Sprintf("script statement %d (line %d, column %d): %s", e.Number, e.Line, e.Column, e.Err)
+}
+
+func (e *ScriptError) Unwrap() error {
+	return e.Err
+}
+
+var (
+	reScriptSetTerm = regexp.MustCompile(`(?is)^SET\s+TERM\s+(\S+)$`)
+	reScriptAutoDDL = regexp.MustCompile(`(?is)^SET\s+AUTODDL(?:\s+(ON|OFF))?$`)
+	reScriptCommit  = regexp.MustCompile(`(?is)^(COMMIT|ROLLBACK)(?:\s+WORK)?$`)
+	// isql settings that only affect its own output or session setup
+	reScriptIgnored = regexp.MustCompile(`(?is)^SET\s+(NAMES|SQL\s+DIALECT|ECHO|BAIL|LIST|COUNT|HEADING|STATS|PLAN|PLANONLY|TIME|WARNINGS|WNG|BLOB|BLOBDISPLAY|WIDTH|ROWCOUNT|EXPLAIN|PER_TABLE_STATS)\b`)
+	reScriptDDL     = regexp.MustCompile(`(?is)^(CREATE|ALTER|RECREATE|DROP|DECLARE|COMMENT|GRANT|REVOKE)\b`)
+)
+
+var scriptQuoteClose = map[rune]rune{'(': ')', '[': ']', '{': '}', '<': '>'}
+
+type scriptScanner struct {
+	src    string
+	pos    int
+	line   int
+	column int
+}
+
+func (s *scriptScanner) next() rune {
+	r, n := utf8.DecodeRuneInString(s.src[s.pos:])
+	s.pos += n
+	if r == '\n' {
+		s.line++
+		s.column = 1
+	} else {
+		s.column++
+	}
+	return r
+}
+
+func (s *scriptScanner) skip(prefix string) {
+	for end := s.pos + len(prefix); s.pos < end; {
+		s.next()
+	}
+}
+
+func (s *scriptScanner) at(prefix string) bool {
+	return prefix != "" && strings.HasPrefix(s.src[s.pos:], prefix)
+}
+
+// skipUntil advances past the closing delimiter, reporting false at the end
+// of the script.
+func (s *scriptScanner) skipUntil(end string) bool {
+	for s.pos < len(s.src) {
+		if s.at(end) {
+			s.skip(end)
+			return true
+		}
+		s.next()
+	}
+	return false
+}
+
+// skipQuoted advances past a literal opened by quote, where a doubled quote
+// stands for itself.
+func (s *scriptScanner) skipQuoted(quote rune) bool {
+	for s.pos < len(s.src) {
+		if s.next() == quote {
+			if !s.at(string(quote)) {
+				return true
+			}
+			s.next()
+		}
+	}
+	return false
+}
+
+// atAltQuote reports whether a Firebird 3 alternative string literal, such
+// as q'{it's}', starts here.
+func (s *scriptScanner) atAltQuote() bool {
+	return (s.at("q'") || s.at("Q'")) && s.pos+2 < len(s.src) && !scriptIdentChar(s.src, s.pos-1)
+}
+
+func (s *scriptScanner) skipAltQuoted() bool {
+	s.skip("q'")
+	open := s.next()
+	close, ok := scriptQuoteClose[open]
+	if !ok {
+		close = open
+	}
+	return s.skipUntil(string(close) + "'")
+}
+
+// parseScript splits a script into statements, honouring SET TERM, string
+// literals and comments. SET TERM itself is consumed and not returned.
+func parseScript(script string) (statements []scriptStatement, err error) {
+	s := &scriptScanner{src: script, line: 1, column: 1}
+	term := ";"
+	start, line, column := -1, 0, 0
+	end := func(stop int) {
+		sql := strings.TrimSpace(script[start:stop])
+		start = -1
+		if sql == "" {
+			return
+		}
+		if m := reScriptSetTerm.FindStringSubmatch(sql); m != nil {
+			term = m[1]
+			return
+		}
+		statements = append(statements, scriptStatement{SQL: sql, Number: len(statements) + 1, Line: line, Column: column})
+	}
+	for s.pos < len(s.src) {
+		if s.at("--") {
+			s.skipUntil("\n")
+			continue
+		}
+		tokenLine, tokenColumn := s.line, s.column
+		if s.at("/*") {
+			s.skip("/*")
+			if !s.skipUntil("*/") {
+				return nil, &ScriptError{Line: tokenLine, Column: tokenColumn, Err: fmt.Errorf("unterminated comment")}
+			}
+			continue
+		}
+		r, _ := utf8.DecodeRuneInString(s.src[s.pos:])
+		if unicode.IsSpace(r) {
+			s.next()
+			continue
+		}
+		if start < 0 {
+			start, line, column = s.pos, tokenLine, tokenColumn
+		}
+		if s.at(term) {
+			stop := s.pos
+			s.skip(term)
+			end(stop)
+			continue
+		}
+		switch {
+		case r == '\'' || r == '"':
+			s.next()
+			if !s.skipQuoted(r) {
+				return nil, &ScriptError{Line: tokenLine, Column: tokenColumn, Err: fmt.Errorf("unterminated string")}
+			}
+		case s.atAltQuote():
+			if !s.skipAltQuoted() {
+				return nil, &ScriptError{Line: tokenLine, Column: tokenColumn, Err: fmt.Errorf("unterminated string")}
+			}
+		default:
+			s.next()
+		}
+	}
+	if start >= 0 {
+		end(len(script))
+	}
+	return
+}
+
+func scriptIdentChar(src string, pos int) bool {
+	if pos < 0 {
+		return false
+	}
+	c := src[pos]
+	return c == '_' || c == '$' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// ExecuteScript runs a script the way isql would. Statements run in
+// autocommit mode unless the connection has a transaction started, in which
+// case DDL is still committed in a transaction of its own while AUTODDL is
+// on. After SET AUTODDL OFF the remaining statements share the connection
+// transaction until the script issues COMMIT or ROLLBACK; a transaction still
+// open at the end is left to the caller.
+func (conn *Connection) ExecuteScript(sql string) (err error) {
+	var statements []scriptStatement
+	if statements, err = parseScript(sql); err != nil {
+		return
+	}
+	autoDDL := true
+	for _, stmt := range statements {
+		if err = conn.executeScriptStatement(stmt.SQL, &autoDDL); err != nil {
+			return &ScriptError{Number: stmt.Number, Line: stmt.Line, Column: stmt.Column, SQL: stmt.SQL, Err: err}
+		}
+	}
+	return
+}
+
+func (conn *Connection) executeScriptStatement(sql string, autoDDL *bool) (err error) {
+	switch {
+	case reScriptIgnored.MatchString(sql):
+		return
+	case reScriptAutoDDL.MatchString(sql):
+		*autoDDL = !strings.EqualFold(reScriptAutoDDL.FindStringSubmatch(sql)[1], "OFF")
+		return
+	case reScriptCommit.MatchString(sql):
+		if strings.EqualFold(reScriptCommit.FindStringSubmatch(sql)[1], "COMMIT") {
+			return conn.Commit()
+		}
+		return conn.Rollback()
+	}
+	if !*autoDDL && !conn.TransactionStarted() {
+		if err = conn.TransactionStart(""); err != nil {
+			return
+		}
+	}
+	var cursor *Cursor
+	if *autoDDL && conn.TransactionStarted() && reScriptDDL.MatchString(sql) {
+		// like isql, commit DDL separately from the pending work
+		var tx *Transaction
+		if tx, err = conn.Begin(""); err != nil {
+			return
+		}
+		if cursor, err = tx.Execute(sql); err == nil && cursor != nil {
+			err = cursor.Close()
+		}
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+		return tx.Commit()
+	}
+	if cursor, err = conn.Execute(sql); err == nil && cursor != nil {
+		err = cursor.Close()
+	}
+	return
+}
@@ -0,0 +1,99 @@
+package fb
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestParseScript(t *testing.T) {
+	st := SuperTest{t}
+	const script = `-- schema; with a comment
+CREATE TABLE T (ID INT, S VARCHAR(20) DEFAULT 'a;b');
+/* block ; comment */ INSERT INTO T (ID, S) VALUES (1, 'it''s; ok');
+SET TERM ^ ;
+CREATE PROCEDURE P AS
+BEGIN
+  INSERT INTO "T" (ID, S) VALUES (2, q'{x;y}');
+END^
+SET TERM ; ^
+COMMIT;
+SELECT 1 FROM RDB$DATABASE`
+
+	statements, err := parseScript(script)
+	if err != nil {
+		t.Fatalf("Unexpected error parsing script: %s", err)
+	}
+	st.MustEqual(5, len(statements))
+	st.Equal("CREATE TABLE T (ID INT, S VARCHAR(20) DEFAULT 'a;b')", statements[0].SQL)
+	st.Equal(2, statements[0].Line)
+	st.Equal(1, statements[0].Column)
+	st.Equal("INSERT INTO T (ID, S) VALUES (1, 'it''s; ok')", statements[1].SQL)
+	st.Equal(3, statements[1].Line)
+	st.Equal(23, statements[1].Column)
+	st.True(strings.HasPrefix(statements[2].SQL, "CREATE PROCEDURE P AS"))
+	st.True(strings.HasSuffix(statements[2].SQL, "END"))
+	st.Equal(5, statements[2].Line)
+	st.Equal(3, statements[2].Number)
+	st.Equal("COMMIT", statements[3].SQL)
+	st.Equal("SELECT 1 FROM RDB$DATABASE", statements[4].SQL)
+
+	_, err = parseScript("SELECT 1 FROM RDB$DATABASE;\nSELECT 'oops FROM RDB$DATABASE;")
+	st.True(err != nil)
+	if serr, ok := err.(*ScriptError); ok {
+		st.Equal(2, serr.Line)
+		st.Equal(8, serr.Column)
+	} else {
+		t.Errorf("Expected ScriptError, got %T", err)
+	}
+	_, err = parseScript("SELECT 1 /* never closed")
+	st.True(err != nil)
+
+	statements, err = parseScript(";;\nSELECT 1 FROM RDB$DATABASE;;\n  ;\n")
+	st.Nil(err)
+	st.MustEqual(1, len(statements))
+	st.Equal(1, statements[0].Number)
+}
+
+func TestExecuteScriptSetTerm(t *testing.T) {
+	st := SuperTest{t}
+	const script = `
+SET SQL DIALECT 3;
+CREATE TABLE TEST (ID INT, S VARCHAR(20));
+SET TERM !! ;
+CREATE TRIGGER TEST_BI FOR TEST BEFORE INSERT AS
+BEGIN
+  IF (NEW.S IS NULL) THEN NEW.S = 'semi;colon';
+END!!
+SET TERM ; !!
+SET AUTODDL OFF;
+INSERT INTO TEST (ID) VALUES (1);
+COMMIT WORK;
+INSERT INTO TEST (ID) VALUES (2);
+ROLLBACK;`
+	os.Remove(TestFilename)
+
+	conn, err := Create(TestConnectionString)
+	if err != nil {
+		t.Fatalf("Unexpected error creating database: %s", err)
+	}
+	defer conn.Drop()
+
+	if err = conn.ExecuteScript(script); err != nil {
+		t.Fatalf("Unexpected error executing script: %s", err)
+	}
+	rows, err := conn.QueryRows("SELECT ID, S FROM TEST")
+	st.Nil(err)
+	st.MustEqual(1, len(rows))
+	st.Equal("semi;colon", rows[0][1])
+
+	err = conn.ExecuteScript("INSERT INTO TEST (ID) VALUES (3);\n  INSERT INTO MISSING (ID) VALUES (4);")
+	serr, ok := err.(*ScriptError)
+	if !ok {
+		t.Fatalf("Expected ScriptError, got %v", err)
+	}
+	st.Equal(2, serr.Number)
+	st.Equal(2, serr.Line)
+	st.Equal(3, serr.Column)
+	st.True(strings.Contains(serr.Error(), "statement 2 (line 2, column 3)"))
+}
@@ -0,0 +1,339 @@
+package fb
+
+/*
+#include <ibase.h>
+#include <stdlib.h>
+*/
+import "C"
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+	"unsafe"
+)
+
+const serviceBufferSize = 0x4000
+
+type Service struct {
+	handle   C.isc_svc_handle
+	Host     string
+	Username string
+	Password string
+}
+
+type ServiceError struct {
+	Action  string
+	Code    int
+	Message string
+	Details []string
+}
+
+func (e *ServiceError) Error() string {
+	msg := fmt.Sprintf("%s failed: %s", e.Action, strings.TrimSpace(e.Message))
+	if len(e.Details) > 0 {
+		msg += "\n" + strings.Join(e.Details, "\n")
+	}
+	return msg
+}
+
+// spb builds a service parameter block; numbers are little endian.
+type spb struct {
+	bytes.Buffer
+}
+
+func (b *spb) addString(tag byte, s string) {
+	b.WriteByte(tag)
+	binary.Write(b, binary.LittleEndian, uint16(len(s)))
+	b.WriteString(s)
+}
+
+func (b *spb) addInt(tag byte, v uint32) {
+	b.WriteByte(tag)
+	binary.Write(b, binary.LittleEndian, v)
+}
+
+func (b *spb) addByte(tag byte, v byte) {
+	b.WriteByte(tag)
+	b.WriteByte(v)
+}
+
+func NewService(parms string) (svc *Service, err error) {
+	p, err := MapFromConnectionString(parms)
+	if err != nil {
+		return nil, err
+	}
+	username, ok := p["username"]
+	if !ok {
+		return nil, errors.New("username parm required")
+	}
+	password, ok := p["password"]
+	if !ok {
+		return nil, errors.New("password parm required")
+	}
+	host, _ := p["host"]
+	if database, ok := p["database"]; ok && host == "" {
+		if i := strings.Index(database, ":"); i > 1 {
+			host = database[:i]
+		}
+	}
+	return &Service{Host: host, Username: username, Password: password}, nil
+}
+
+func ConnectService(parms string) (svc *Service, err error) {
+	if svc, err = NewService(parms); err != nil {
+		return
+	}
+	err = svc.Attach()
+	return
+}
+
+func (svc *Service) Attach() error {
+	var isc_status [20]C.ISC_STATUS
+
+	name := "service_mgr"
+	if svc.Host != "" {
+		name = svc.Host + ":" + name
+	}
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+
+	var buf bytes.Buffer
+	buf.WriteByte(C.isc_spb_version)
+	buf.WriteByte(C.isc_spb_current_version)
+	buf.WriteByte(C.isc_spb_user_name)
+	buf.WriteByte(byte(len(svc.Username)))
+	buf.WriteString(svc.Username)
+	buf.WriteByte(C.isc_spb_password)
+	buf.WriteByte(byte(len(svc.Password)))
+	buf.WriteString(svc.Password)
+	cspb := C.CString(buf.String())
+	defer C.free(unsafe.Pointer(cspb))
+
+	C.isc_service_attach(&isc_status[0], 0, (*C.ISC_SCHAR)(unsafe.Pointer(cname)), &svc.handle,
+		C.ushort(buf.Len()), (*C.ISC_SCHAR)(unsafe.Pointer(cspb)))
+	return fbErrorCheck(&isc_status)
+}
+
+func (svc *Service) check() error {
+	if svc.handle == 0 {
+		return &Error{0, "closed service connection"}
+	}
+	return nil
+}
+
+func (svc *Service) Close() (err error) {
+	var isc_status [20]C.ISC_STATUS
+
+	if svc.handle == 0 {
+		return
+	}
+	C.isc_service_detach(&isc_status[0], &svc.handle)
+	svc.handle = 0
+	return fbErrorCheck(&isc_status)
+}
+
+func serviceError(action string, err error, details []string) error {
+	if fbErr, ok := err.(*Error); ok {
+		return &ServiceError{Action: action, Code: fbErr.Code, Message: fbErr.Message, Details: details}
+	}
+	return err
+}
+
+func (svc *Service) start(request *spb) error {
+	var isc_status [20]C.ISC_STATUS
+
+	if err := svc.check(); err != nil {
+		return err
+	}
+	creq := C.CBytes(request.Bytes())
+	defer C.free(creq)
+	C.isc_service_start(&isc_status[0], &svc.handle, nil, C.ushort(request.Len()), (*C.ISC_SCHAR)(creq))
+	return fbErrorCheck(&isc_status)
+}
+
+func (svc *Service) query(items []byte, result []byte) error {
+	var isc_status [20]C.ISC_STATUS
+
+	citems := C.CBytes(items)
+	defer C.free(citems)
+	cresult := (*C.ISC_SCHAR)(C.malloc(C.size_t(len(result))))
+	defer C.free(unsafe.Pointer(cresult))
+	C.isc_service_query(&isc_status[0], &svc.handle, nil, 0, nil,
+		C.ushort(len(items)), (*C.ISC_SCHAR)(citems), C.ushort(len(result)), cresult)
+	if err := fbErrorCheck(&isc_status); err != nil {
+		return err
+	}
+	copy(result, C.GoBytes(unsafe.Pointer(cresult), C.int(len(result))))
+	return nil
+}
+
+// output reads the text produced by the running service action line by
+// line until it is exhausted.
+func (svc *Service) output(line func(string)) error {
+	result := make([]byte, serviceBufferSize)
+	for {
+		if err := svc.query([]byte{C.isc_info_svc_line}, result); err != nil {
+			return err
+		}
+		if result[0] != C.isc_info_svc_line {
+			return nil
+		}
+		n := int(binary.LittleEndian.Uint16(result[1:3]))
+		if n == 0 {
+			return nil
+		}
+		// the server turns each newline into a trailing space
+		line(strings.TrimRight(string(result[3:3+n]), " "))
+	}
+}
+
+// run starts the action and collects its output, reporting lines through
+// verbose when it is set. Lines that look like errors are attached to the
+// returned ServiceError.
+func (svc *Service) run(action string, request *spb, verbose func(string)) (lines []string, err error) {
+	if err = svc.start(request); err != nil {
+		return nil, serviceError(action, err, nil)
+	}
+	var details []string
+	err = svc.output(func(line string) {
+		lines = append(lines, line)
+		if strings.Contains(line, "ERROR:") {
+			details = append(details, line)
+		}
+		if verbose != nil {
+			verbose(line)
+		}
+	})
+	if err != nil {
+		return lines, serviceError(action, err, details)
+	}
+	if len(details) > 0 {
+		return lines, &ServiceError{Action: action, Message: details[0], Details: details}
+	}
+	return
+}
+
+type BackupOptions struct {
+	IgnoreChecksums  bool
+	IgnoreLimbo      bool
+	MetadataOnly     bool
+	NoGarbageCollect bool
+	OldDescriptions  bool
+	NonTransportable bool
+	ConvertExternal  bool
+	NoTriggers       bool
+	Factor           int
+	Verbose          func(line string)
+}
+
+func (opts *BackupOptions) flags() (flags uint32) {
+	if opts.IgnoreChecksums {
+		flags |= C.isc_spb_bkp_ignore_checksums
+	}
+	if opts.IgnoreLimbo {
+		flags |= C.isc_spb_bkp_ignore_limbo
+	}
+	if opts.MetadataOnly {
+		flags |= C.isc_spb_bkp_metadata_only
+	}
+	if opts.NoGarbageCollect {
+		flags |= C.isc_spb_bkp_no_garbage_collect
+	}
+	if opts.OldDescriptions {
+		flags |= C.isc_spb_bkp_old_descriptions
+	}
+	if opts.NonTransportable {
+		flags |= C.isc_spb_bkp_non_transportable
+	}
+	if opts.ConvertExternal {
+		flags |= C.isc_spb_bkp_convert
+	}
+	if opts.NoTriggers {
+		flags |= C.isc_spb_bkp_no_triggers
+	}
+	return
+}
+
+func (svc *Service) Backup(database, backupFile string, opts *BackupOptions) error {
+	if opts == nil {
+		opts = &BackupOptions{}
+	}
+	request := &spb{}
+	request.WriteByte(C.isc_action_svc_backup)
+	request.addString(C.isc_spb_dbname, database)
+	request.addString(C.isc_spb_bkp_file, backupFile)
+	if opts.Factor > 0 {
+		request.addInt(C.isc_spb_bkp_factor, uint32(opts.Factor))
+	}
+	request.addInt(C.isc_spb_options, opts.flags())
+	if opts.Verbose != nil {
+		request.WriteByte(C.isc_spb_verbose)
+	}
+	_, err := svc.run("backup", request, opts.Verbose)
+	return err
+}
+
+type RestoreOptions struct {
+	Replace           bool
+	DeactivateIndexes bool
+	NoShadow          bool
+	NoValidity        bool
+	OneAtATime        bool
+	UseAllSpace       bool
+	ReadOnly          bool
+	PageSize          int
+	Buffers           int
+	Verbose           func(line string)
+}
+
+func (opts *RestoreOptions) flags() (flags uint32) {
+	if opts.Replace {
+		flags |= C.isc_spb_res_replace
+	} else {
+		flags |= C.isc_spb_res_create
+	}
+	if opts.DeactivateIndexes {
+		flags |= C.isc_spb_res_deactivate_idx
+	}
+	if opts.NoShadow {
+		flags |= C.isc_spb_res_no_shadow
+	}
+	if opts.NoValidity {
+		flags |= C.isc_spb_res_no_validity
+	}
+	if opts.OneAtATime {
+		flags |= C.isc_spb_res_one_at_a_time
+	}
+	if opts.UseAllSpace {
+		flags |= C.isc_spb_res_use_all_space
+	}
+	return
+}
+
+func (svc *Service) Restore(backupFile, database string, opts *RestoreOptions) error {
+	if opts == nil {
+		opts = &RestoreOptions{}
+	}
+	request := &spb{}
+	request.WriteByte(C.isc_action_svc_restore)
+	request.addString(C.isc_spb_bkp_file, backupFile)
+	request.addString(C.isc_spb_dbname, database)
+	if opts.PageSize > 0 {
+		request.addInt(C.isc_spb_res_page_size, uint32(opts.PageSize))
+	}
+	if opts.Buffers > 0 {
+		request.addInt(C.isc_spb_res_buffers, uint32(opts.Buffers))
+	}
+	if opts.ReadOnly {
+		request.addByte(C.isc_spb_res_access_mode, C.isc_spb_res_am_readonly)
+	}
+	request.addInt(C.isc_spb_options, opts.flags())
+	if opts.Verbose != nil {
+		request.WriteByte(C.isc_spb_verbose)
+	}
+	_, err := svc.run("restore", request, opts.Verbose)
+	return err
+}
@@ -0,0 +1,107 @@
+package fb
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+const (
+	TestBackupFilename   = "/var/fbdata/go-fb-test.fbk"
+	TestRestoredFilename = "/var/fbdata/go-fb-test-restored.fdb"
+)
+
+func TestServiceSpb(t *testing.T) {
+	st := SuperTest{t}
+	request := &spb{}
+	request.WriteByte(1)
+	request.addString(2, "abc")
+	request.addInt(3, 0x01020304)
+	request.addByte(4, 5)
+	st.Equal("\x01\x02\x03\x00abc\x03\x04\x03\x02\x01\x04\x05", request.String())
+}
+
+func TestNewService(t *testing.T) {
+	st := SuperTest{t}
+	svc, err := NewService(TestConnectionString)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	st.Equal("localhost", svc.Host)
+	st.Equal("gotest", svc.Username)
+
+	svc, err = NewService("database=/var/fbdata/test.fdb;username=a;password=b")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	st.Equal("", svc.Host)
+
+	_, err = NewService("host=localhost;password=b")
+	st.True(err != nil)
+}
+
+func TestServiceBackupRestore(t *testing.T) {
+	st := SuperTest{t}
+	os.Remove(TestFilename)
+	os.Remove(TestBackupFilename)
+	os.Remove(TestRestoredFilename)
+
+	conn, err := Create(TestConnectionString)
+	if err != nil {
+		t.Fatalf("Unexpected error creating database: %s", err)
+	}
+	defer conn.Drop()
+	if _, err = conn.Execute("CREATE TABLE TEST (ID INTEGER, NAME VARCHAR(20))"); err != nil {
+		t.Fatalf("Error creating table: %s", err)
+	}
+	if _, err = conn.Execute("INSERT INTO TEST VALUES (1, 'backed up')"); err != nil {
+		t.Fatalf("Error inserting: %s", err)
+	}
+
+	svc, err := ConnectService(TestConnectionString)
+	if err != nil {
+		t.Fatalf("Unexpected error attaching to service manager: %s", err)
+	}
+	defer svc.Close()
+
+	var lines []string
+	opts := &BackupOptions{Verbose: func(line string) { lines = append(lines, line) }}
+	if err = svc.Backup(TestFilename, TestBackupFilename, opts); err != nil {
+		t.Fatalf("Unexpected error in backup: %s", err)
+	}
+	st.True(len(lines) > 0)
+	defer os.Remove(TestBackupFilename)
+
+	if err = svc.Restore(TestBackupFilename, TestRestoredFilename, &RestoreOptions{Replace: true, PageSize: 4096}); err != nil {
+		t.Fatalf("Unexpected error in restore: %s", err)
+	}
+	restored, err := Connect(strings.Replace(TestConnectionString, TestFilename, TestRestoredFilename, 1))
+	if err != nil {
+		t.Fatalf("Unexpected error connecting to restored database: %s", err)
+	}
+	defer restored.Drop()
+	row, err := restored.QueryRow("SELECT NAME FROM TEST WHERE ID = 1")
+	if err != nil {
+		t.Fatalf("Unexpected error in select: %s", err)
+	}
+	st.Equal("backed up", row[0])
+}
+
+func TestServiceBackupError(t *testing.T) {
+	st := SuperTest{t}
+	svc, err := ConnectService(TestConnectionString)
+	if err != nil {
+		t.Fatalf("Unexpected error attaching to service manager: %s", err)
+	}
+	defer svc.Close()
+
+	err = svc.Backup("/var/fbdata/no-such-database.fdb", TestBackupFilename, nil)
+	svcErr, ok := err.(*ServiceError)
+	if !ok {
+		t.Fatalf("Expected *ServiceError, got %v", err)
+	}
+	st.Equal("backup", svcErr.Action)
+
+	st.Nil(svc.Close())
+	st.True(svc.Backup(TestFilename, TestBackupFilename, nil) != nil)
+}
@@ -0,0 +1,92 @@
+package fb
+
+/*
+#include <ibase.h>
+#include "fb.h"
+*/
+import "C"
+
+type Statement struct {
+	connection *Connection
+	cursor     *Cursor
+	Params     []*Column
+	Columns    []*Column
+	ColumnsMap map[string]*Column
+}
+
+func (conn *Connection) Prepare(sql string) (stmt *Statement, err error) {
+	var cursor *Cursor
+	if cursor, err = newCursor(conn); err != nil {
+		return
+	}
+	if conn.TransactionStarted() {
+		err = cursor.prepare(sql)
+	} else {
+		if err = conn.TransactionStart(""); err != nil {
+			cursor.drop()
+			return nil, err
+		}
+		err = cursor.prepare(sql)
+		// the statement stays prepared once the transaction ends
+		if rerr := conn.Rollback(); err == nil {
+			err = rerr
+		}
+	}
+	if err != nil {
+		cursor.drop()
+		return nil, err
+	}
+	stmt = &Statement{connection: conn, cursor: cursor}
+	cursor.statement = stmt
+	stmt.Params = columnsFromSqlda(cursor.i_sqlda, conn.database.LowercaseNames)
+	stmt.Columns = columnsFromSqlda(cursor.o_sqlda, conn.database.LowercaseNames)
+	stmt.ColumnsMap = columnsMapFromSlice(stmt.Columns)
+	return stmt, nil
+}
+
+func (stmt *Statement) check() error {
+	if stmt.cursor == nil {
+		return &Error{Message: "closed statement"}
+	}
+	return stmt.connection.check()
+}
+
+func (stmt *Statement) Close() (err error) {
+	if err = stmt.check(); err != nil {
+		return
+	}
+	cursor := stmt.cursor
+	stmt.cursor = nil
+	if cursor.open {
+		if err = cursor.close(); err != nil {
+			return
+		}
+	}
+	return cursor.drop()
+}
+
+// Execute runs the prepared statement with args. For queries the returned
+// Cursor is owned by the statement and is closed by the next Execute.
+func (stmt *Statement) Execute(args ...interface{}) (cursor *Cursor, err error) {
+	if err = stmt.check(); err != nil {
+		return
+	}
+	stmt.resetParams()
+	rowsAffected, err := stmt.cursor.execute("", args...)
+	if rowsAffected >= 0 {
+		stmt.connection.rowsAffected = rowsAffected
+	}
+	if stmt.cursor.open {
+		cursor = stmt.cursor
+	}
+	return
+}
+
+// setInputParams shrinks CHAR lengths to the bound value, so restore the
+// described lengths before binding again.
+func (stmt *Statement) resetParams() {
+	for i, param := range stmt.Params {
+		ivar := C.sqlda_sqlvar(stmt.cursor.i_sqlda, C.ISC_SHORT(i))
+		ivar.sqllen = C.ISC_SHORT(param.Length)
+	}
+}
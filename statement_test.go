@@ -0,0 +1,108 @@
+package fb
+
+import (
+	"io"
+	"os"
+	"testing"
+)
+
+func TestPrepare(t *testing.T) {
+	st := SuperTest{t}
+	os.Remove(TestFilename)
+
+	conn, err := Create(TestConnectionString)
+	if err != nil {
+		t.Fatalf("Unexpected error creating database: %s", err)
+	}
+	defer conn.Drop()
+	if _, err = conn.Execute("CREATE TABLE TEST (ID INT NOT NULL, NAME VARCHAR(20))"); err != nil {
+		t.Fatalf("Unexpected error creating table: %s", err)
+	}
+
+	stmt, err := conn.Prepare("SELECT ID, NAME FROM TEST WHERE ID > ?")
+	if err != nil {
+		t.Fatalf("Unexpected error preparing statement: %s", err)
+	}
+	defer stmt.Close()
+	st.MustEqual(1, len(stmt.Params))
+	st.Equal("INTEGER", stmt.Params[0].SqlType)
+	st.MustEqual(2, len(stmt.Columns))
+	st.Equal("ID", stmt.Columns[0].Name)
+	st.Equal("NAME", stmt.Columns[1].Name)
+	st.Equal("VARCHAR", stmt.ColumnsMap["NAME"].SqlType)
+	if conn.TransactionStarted() {
+		t.Error("Prepare should not leave a transaction started")
+	}
+}
+
+func TestStatementExecuteMany(t *testing.T) {
+	st := SuperTest{t}
+	os.Remove(TestFilename)
+
+	conn, err := Create(TestConnectionString)
+	if err != nil {
+		t.Fatalf("Unexpected error creating database: %s", err)
+	}
+	defer conn.Drop()
+	if _, err = conn.Execute("CREATE TABLE TEST (ID INT NOT NULL, NAME CHAR(10))"); err != nil {
+		t.Fatalf("Unexpected error creating table: %s", err)
+	}
+
+	insert, err := conn.Prepare("INSERT INTO TEST (ID, NAME) VALUES (?, ?)")
+	if err != nil {
+		t.Fatalf("Unexpected error preparing insert: %s", err)
+	}
+	defer insert.Close()
+	for i := 0; i < 10; i++ {
+		if _, err = insert.Execute(i, genC10(i)); err != nil {
+			t.Fatalf("Unexpected error inserting row %d: %s", i, err)
+		}
+		st.Equal(1, conn.RowsAffected())
+	}
+
+	query, err := conn.Prepare("SELECT COUNT(*) FROM TEST WHERE ID >= ?")
+	if err != nil {
+		t.Fatalf("Unexpected error preparing query: %s", err)
+	}
+	defer query.Close()
+	for i := 0; i < 10; i++ {
+		cursor, err := query.Execute(i)
+		if err != nil {
+			t.Fatalf("Unexpected error executing query: %s", err)
+		}
+		if !cursor.Next() {
+			t.Fatalf("Unexpected error fetching count: %s", cursor.Err())
+		}
+		var count int
+		if err = cursor.Scan(&count); err != nil {
+			t.Fatalf("Unexpected error scanning count: %s", err)
+		}
+		st.Equal(10-i, count)
+		st.False(cursor.Next())
+		st.Equal(io.EOF, cursor.Err())
+		if err = cursor.Close(); err != nil {
+			t.Fatalf("Unexpected error closing cursor: %s", err)
+		}
+	}
+}
+
+func TestStatementClose(t *testing.T) {
+	os.Remove(TestFilename)
+
+	conn, err := Create(TestConnectionString)
+	if err != nil {
+		t.Fatalf("Unexpected error creating database: %s", err)
+	}
+	defer conn.Drop()
+
+	stmt, err := conn.Prepare("SELECT * FROM RDB$DATABASE")
+	if err != nil {
+		t.Fatalf("Unexpected error preparing statement: %s", err)
+	}
+	if err = stmt.Close(); err != nil {
+		t.Fatalf("Unexpected error closing statement: %s", err)
+	}
+	if _, err = stmt.Execute(); err == nil {
+		t.Error("Expected error executing closed statement")
+	}
+}
@@ -0,0 +1,226 @@
+package fb
+
+/*
+#include <ibase.h>
+*/
+import "C"
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type StatsFlags uint32
+
+const (
+	StatsDataPages       StatsFlags = C.isc_spb_sts_data_pages
+	StatsHeaderPages     StatsFlags = C.isc_spb_sts_hdr_pages
+	StatsIndexPages      StatsFlags = C.isc_spb_sts_idx_pages
+	StatsSystemRelations StatsFlags = C.isc_spb_sts_sys_relations
+	StatsRecordVersions  StatsFlags = C.isc_spb_sts_record_versions
+)
+
+type DatabaseStats struct {
+	Text   string
+	Header HeaderStats
+	Tables []*TableStats
+}
+
+type HeaderStats struct {
+	Flags             int
+	Generation        int64
+	PageSize          int
+	ODSVersion        string
+	OldestTransaction int64
+	OldestActive      int64
+	OldestSnapshot    int64
+	NextTransaction   int64
+	NextAttachmentID  int64
+	Implementation    string
+	ShadowCount       int
+	PageBuffers       int
+	Dialect           int
+	CreationDate      time.Time
+	Attributes        []string
+	SweepInterval     int
+	Values            map[string]string
+}
+
+type TableStats struct {
+	Name                 string
+	ID                   int
+	PrimaryPointerPage   int64
+	IndexRootPage        int64
+	AverageRecordLength  float64
+	TotalRecords         int64
+	AverageVersionLength float64
+	TotalVersions        int64
+	MaxVersions          int64
+	DataPages            int64
+	DataPageSlots        int64
+	AverageFill          int
+	FillDistribution     []int64
+	Indexes              []*IndexStats
+	Values               map[string]string
+}
+
+type IndexStats struct {
+	Name              string
+	ID                int
+	RootPage          int64
+	Depth             int
+	LeafBuckets       int64
+	Nodes             int64
+	AverageDataLength float64
+	TotalDup          int64
+	MaxDup            int64
+	FillDistribution  []int64
+	Values            map[string]string
+}
+
+func (svc *Service) DatabaseStats(database string, flags StatsFlags) (stats *DatabaseStats, err error) {
+	if flags == 0 {
+		flags = StatsHeaderPages
+	}
+	request := &spb{}
+	request.WriteByte(C.isc_action_svc_db_stats)
+	request.addString(C.isc_spb_dbname, database)
+	request.addInt(C.isc_spb_options, uint32(flags))
+	var lines []string
+	if lines, err = svc.run("database statistics", request, nil); err != nil {
+		return
+	}
+	return parseDatabaseStats(strings.Join(lines, "\n")), nil
+}
+
+var (
+	// gstat separates header keys from values with tabs
+	reStatsHeaderItem = regexp.MustCompile(`^\s+([A-Za-z][A-Za-z ]*?):?(?:\t|\s{2,})\s*(\S.*)$`)
+	reStatsTable      = regexp.MustCompile(`^(\S.*) \((\d+)\)$`)
+	reStatsIndex      = regexp.MustCompile(`^\s+Index (\S+) \((\d+)\)$`)
+	reStatsFill       = regexp.MustCompile(`^\s*\d+ - \d+% = (\d+)$`)
+)
+
+func parseDatabaseStats(text string) *DatabaseStats {
+	stats := &DatabaseStats{Text: text}
+	stats.Header.Values = make(map[string]string)
+	var table *TableStats
+	var index *IndexStats
+	inHeader := false
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimRight(line, " \t\r")
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "":
+			continue
+		case strings.HasPrefix(trimmed, "Database header page information"):
+			inHeader = true
+		case trimmed == "*END*":
+			inHeader = false
+		case inHeader:
+			if m := reStatsHeaderItem.FindStringSubmatch(line); m != nil {
+				stats.Header.Values[m[1]] = strings.TrimSpace(m[2])
+			}
+		case reStatsTable.MatchString(line):
+			m := reStatsTable.FindStringSubmatch(line)
+			id, _ := strconv.Atoi(m[2])
+			table = &TableStats{Name: m[1], ID: id, Values: make(map[string]string)}
+			index = nil
+			stats.Tables = append(stats.Tables, table)
+		case table != nil && reStatsIndex.MatchString(line):
+			m := reStatsIndex.FindStringSubmatch(line)
+			id, _ := strconv.Atoi(m[2])
+			index = &IndexStats{Name: m[1], ID: id, Values: make(map[string]string)}
+			table.Indexes = append(table.Indexes, index)
+		case table != nil && reStatsFill.MatchString(line):
+			n, _ := strconv.ParseInt(reStatsFill.FindStringSubmatch(line)[1], 10, 64)
+			if index != nil {
+				index.FillDistribution = append(index.FillDistribution, n)
+			} else {
+				table.FillDistribution = append(table.FillDistribution, n)
+			}
+		case table != nil && line != trimmed:
+			values := table.Values
+			if index != nil {
+				values = index.Values
+			}
+			for _, item := range strings.Split(trimmed, ", ") {
+				if kv := strings.SplitN(item, ":", 2); len(kv) == 2 {
+					values[strings.ToLower(strings.TrimSpace(kv[0]))] = strings.TrimSpace(kv[1])
+				}
+			}
+		}
+	}
+	stats.Header.fill()
+	for _, table := range stats.Tables {
+		table.fill()
+		for _, index := range table.Indexes {
+			index.fill()
+		}
+	}
+	return stats
+}
+
+func statsInt(values map[string]string, key string) int64 {
+	i, _ := strconv.ParseInt(strings.TrimSuffix(strings.Fields(values[key] + " 0")[0], "%"), 10, 64)
+	return i
+}
+
+func statsFloat(values map[string]string, key string) float64 {
+	f, _ := strconv.ParseFloat(strings.Fields(values[key] + " 0")[0], 64)
+	return f
+}
+
+func (h *HeaderStats) fill() {
+	v := h.Values
+	h.Flags = int(statsInt(v, "Flags"))
+	h.Generation = statsInt(v, "Generation")
+	h.PageSize = int(statsInt(v, "Page size"))
+	h.ODSVersion = v["ODS version"]
+	h.OldestTransaction = statsInt(v, "Oldest transaction")
+	h.OldestActive = statsInt(v, "Oldest active")
+	h.OldestSnapshot = statsInt(v, "Oldest snapshot")
+	h.NextTransaction = statsInt(v, "Next transaction")
+	h.NextAttachmentID = statsInt(v, "Next attachment ID")
+	h.Implementation = v["Implementation"]
+	h.ShadowCount = int(statsInt(v, "Shadow count"))
+	h.PageBuffers = int(statsInt(v, "Page buffers"))
+	h.Dialect = int(statsInt(v, "Database dialect"))
+	h.CreationDate, _ = time.Parse("Jan 2, 2006 15:04:05", v["Creation date"])
+	if attributes := v["Attributes"]; attributes != "" {
+		h.Attributes = strings.Split(attributes, ", ")
+	}
+	h.SweepInterval = int(statsInt(v, "Sweep interval"))
+}
+
+func (t *TableStats) fill() {
+	v := t.Values
+	t.PrimaryPointerPage = statsInt(v, "primary pointer page")
+	t.IndexRootPage = statsInt(v, "index root page")
+	t.AverageRecordLength = statsFloat(v, "average record length")
+	t.TotalRecords = statsInt(v, "total records")
+	t.AverageVersionLength = statsFloat(v, "average version length")
+	t.TotalVersions = statsInt(v, "total versions")
+	t.MaxVersions = statsInt(v, "max versions")
+	t.DataPages = statsInt(v, "data pages")
+	t.DataPageSlots = statsInt(v, "data page slots")
+	t.AverageFill = int(statsInt(v, "average fill"))
+}
+
+func (i *IndexStats) fill() {
+	v := i.Values
+	i.RootPage = statsInt(v, "root page")
+	i.Depth = int(statsInt(v, "depth"))
+	i.LeafBuckets = statsInt(v, "leaf buckets")
+	i.Nodes = statsInt(v, "nodes")
+	// Firebird 3 renamed the average data length to average node length
+	if _, ok := v["average node length"]; ok {
+		i.AverageDataLength = statsFloat(v, "average node length")
+	} else {
+		i.AverageDataLength = statsFloat(v, "average data length")
+	}
+	i.TotalDup = statsInt(v, "total dup")
+	i.MaxDup = statsInt(v, "max dup")
+}
@@ -0,0 +1,169 @@
+package fb
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+const sampleStats = `
+Database "/var/fbdata/go-fb-test.fdb"
+Gstat execution time Tue Mar  2 10:00:00 2021
+
+Database header page information:
+	Flags			0
+	Generation		24
+	System Change Number	0
+	Page size		4096
+	ODS version		12.0
+	Oldest transaction	10
+	Oldest active		11
+	Oldest snapshot		11
+	Next transaction	15
+	Sequence number		0
+	Next attachment ID	5
+	Implementation		HW=AMD/Intel/x64 little-endian OS=Linux CC=gcc
+	Shadow count		0
+	Page buffers		0
+	Next header page	0
+	Database dialect	3
+	Creation date		Mar 1, 2021 9:30:15
+	Attributes		force write, no reserve
+
+    Variable header data:
+	Sweep interval:		20000
+	*END*
+
+
+Analyzing database pages ...
+TEST (128)
+    Primary pointer page: 166, Index root page: 167
+    Total formats: 1, used formats: 1
+    Average record length: 12.50, total records: 3
+    Average version length: 0.00, total versions: 0, max versions: 0
+    Average fragment length: 0.00, total fragments: 0, max fragments: 0
+    Average unpacked length: 20.00, compression ratio: 1.60
+    Pointer pages: 1, data page slots: 1
+    Data pages: 1, average fill: 6%
+    Primary pages: 1, secondary pages: 0, swept pages: 0
+    Empty pages: 0, full pages: 0
+    Fill distribution:
+	 0 - 19% = 1
+	20 - 39% = 0
+	40 - 59% = 0
+	60 - 79% = 0
+	80 - 99% = 0
+
+    Index RDB$PRIMARY1 (0)
+	Root page: 170, depth: 1, leaf buckets: 1, nodes: 3
+	Average node length: 10.67, total dup: 0, max dup: 0
+	Average key length: 8.00, compression ratio: 0.50
+	Average prefix length: 3.67, average data length: 0.33
+	Clustering factor: 1, ratio: 0.33
+	Fill distribution:
+	     0 - 19% = 1
+	    20 - 39% = 0
+	    40 - 59% = 0
+	    60 - 79% = 0
+	    80 - 99% = 0
+
+ORDER LINES (129)
+    Primary pointer page: 180, Index root page: 181
+    Total formats: 1, used formats: 1
+    Average record length: 0.00, total records: 0
+    Average version length: 0.00, total versions: 0, max versions: 0
+    Average fragment length: 0.00, total fragments: 0, max fragments: 0
+    Average unpacked length: 0.00, compression ratio: 0.00
+    Pointer pages: 1, data page slots: 0
+    Data pages: 0, average fill: 0%
+    Primary pages: 0, secondary pages: 0, swept pages: 0
+    Empty pages: 0, full pages: 0
+    Fill distribution:
+	 0 - 19% = 0
+	20 - 39% = 0
+	40 - 59% = 0
+	60 - 79% = 0
+	80 - 99% = 0
+
+Gstat completion time Tue Mar  2 10:00:01 2021
+`
+
+func TestParseDatabaseStats(t *testing.T) {
+	st := SuperTest{t}
+	stats := parseDatabaseStats(sampleStats)
+
+	h := stats.Header
+	st.Equal(4096, h.PageSize)
+	st.Equal("12.0", h.ODSVersion)
+	st.Equal(int64(10), h.OldestTransaction)
+	st.Equal(int64(11), h.OldestActive)
+	st.Equal(int64(11), h.OldestSnapshot)
+	st.Equal(int64(15), h.NextTransaction)
+	st.Equal(int64(5), h.NextAttachmentID)
+	st.Equal(3, h.Dialect)
+	st.Equal(20000, h.SweepInterval)
+	st.Equal(time.Date(2021, 3, 1, 9, 30, 15, 0, time.UTC), h.CreationDate)
+	st.MustEqual(2, len(h.Attributes))
+	st.Equal("no reserve", h.Attributes[1])
+
+	st.MustEqual(2, len(stats.Tables))
+	table := stats.Tables[0]
+	st.Equal("TEST", table.Name)
+	st.Equal(128, table.ID)
+	st.Equal(int64(166), table.PrimaryPointerPage)
+	st.Equal(12.5, table.AverageRecordLength)
+	st.Equal(int64(3), table.TotalRecords)
+	st.Equal(6, table.AverageFill)
+	st.Equal(5, len(table.FillDistribution))
+	st.Equal(int64(1), table.FillDistribution[0])
+	st.Equal("1", table.Values["used formats"])
+
+	st.MustEqual(1, len(table.Indexes))
+	index := table.Indexes[0]
+	st.Equal("RDB$PRIMARY1", index.Name)
+	st.Equal(int64(170), index.RootPage)
+	st.Equal(1, index.Depth)
+	st.Equal(int64(3), index.Nodes)
+	st.Equal(10.67, index.AverageDataLength)
+	st.Equal(5, len(index.FillDistribution))
+
+	st.Equal("ORDER LINES", stats.Tables[1].Name)
+	st.Equal(129, stats.Tables[1].ID)
+	st.Equal(0, len(stats.Tables[1].Indexes))
+}
+
+func TestServiceDatabaseStats(t *testing.T) {
+	st := SuperTest{t}
+	os.Remove(TestFilename)
+
+	conn, err := Create(TestConnectionString)
+	if err != nil {
+		t.Fatalf("Unexpected error creating database: %s", err)
+	}
+	defer conn.Drop()
+	if _, err = conn.Execute("CREATE TABLE TEST (ID INTEGER NOT NULL PRIMARY KEY)"); err != nil {
+		t.Fatalf("Error creating table: %s", err)
+	}
+
+	svc, err := ConnectService(TestConnectionString)
+	if err != nil {
+		t.Fatalf("Unexpected error attaching to service manager: %s", err)
+	}
+	defer svc.Close()
+
+	stats, err := svc.DatabaseStats(TestFilename, 0)
+	if err != nil {
+		t.Fatalf("Unexpected error in stats: %s", err)
+	}
+	st.Equal(1024, stats.Header.PageSize)
+	st.True(stats.Header.NextTransaction > 0)
+	st.Equal(0, len(stats.Tables))
+
+	stats, err = svc.DatabaseStats(TestFilename, StatsDataPages|StatsIndexPages)
+	if err != nil {
+		t.Fatalf("Unexpected error in stats: %s", err)
+	}
+	st.MustEqual(1, len(stats.Tables))
+	st.Equal("TEST", stats.Tables[0].Name)
+	st.Equal(1, len(stats.Tables[0].Indexes))
+}
@@ -0,0 +1,258 @@
+package fb
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+// structPlan maps column names to the index paths of struct fields, first by
+// exact name and then case insensitively.
+type structPlan struct {
+	exact  map[string][]int
+	folded map[string][]int
+}
+
+var structPlans sync.Map
+
+var (
+	scannerType = reflect.TypeOf((*Scanner)(nil)).Elem()
+	timeType    = reflect.TypeOf(time.Time{})
+)
+
+func structPlanFor(t reflect.Type) *structPlan {
+	if plan, ok := structPlans.Load(t); ok {
+		return plan.(*structPlan)
+	}
+	plan := &structPlan{exact: make(map[string][]int), folded: make(map[string][]int)}
+	depths := make(map[string]int)
+	foldedDepths := make(map[string]int)
+	ambiguous := make(map[string]bool)
+	var walk func(t reflect.Type, index []int)
+	walk = func(t reflect.Type, index []int) {
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			tag := f.Tag.Get("fb")
+			if tag == "-" {
+				continue
+			}
+			path := append(append([]int(nil), index...), i)
+			if f.Anonymous && tag == "" {
+				ft := f.Type
+				if ft.Kind() == reflect.Ptr {
+					ft = ft.Elem()
+				}
+				if isEmbeddedStruct(ft) {
+					walk(ft, path)
+					continue
+				}
+			}
+			if f.PkgPath != "" {
+				continue
+			}
+			name := tag
+			if name == "" {
+				name = f.Name
+			}
+			// shallower fields hide deeper ones, as with Go field selectors
+			if d, ok := depths[name]; !ok || len(path) < d {
+				plan.exact[name], depths[name] = path, len(path)
+			}
+			key := strings.ToUpper(name)
+			if d, ok := foldedDepths[key]; !ok || len(path) < d {
+				plan.folded[key], foldedDepths[key] = path, len(path)
+				ambiguous[key] = false
+			} else if len(path) == d {
+				ambiguous[key] = true
+			}
+		}
+	}
+	walk(t, nil)
+	for key, ok := range ambiguous {
+		if ok {
+			delete(plan.folded, key)
+		}
+	}
+	actual, _ := structPlans.LoadOrStore(t, plan)
+	return actual.(*structPlan)
+}
+
+func isEmbeddedStruct(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && t != timeType && !reflect.PtrTo(t).Implements(scannerType)
+}
+
+func (plan *structPlan) lookup(column string) []int {
+	if path, ok := plan.exact[column]; ok {
+		return path
+	}
+	return plan.folded[strings.ToUpper(column)]
+}
+
+// fieldByPath walks to a possibly embedded field, allocating nil embedded
+// pointers on the way.
+func fieldByPath(v reflect.Value, path []int) (reflect.Value, error) {
+	for i, x := range path {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				if !v.CanSet() {
+					return v, fmt.Errorf("cannot allocate embedded pointer to unexported %s", v.Type().Elem())
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, nil
+}
+
+func setField(f reflect.Value, v interface{}) error {
+	if v != nil && reflect.TypeOf(v).AssignableTo(f.Type()) {
+		f.Set(reflect.ValueOf(v))
+		return nil
+	}
+	if f.Kind() == reflect.Ptr {
+		if v == nil && !f.Type().Implements(scannerType) {
+			f.Set(reflect.Zero(f.Type()))
+			return nil
+		}
+		// a fresh value keeps rows from sharing what the pointer referred to
+		f.Set(reflect.New(f.Type().Elem()))
+		f = f.Elem()
+	}
+	dest := f.Addr().Interface()
+	switch dest.(type) {
+	case *bool, *[]byte, *int, *int16, *int32, *int64, *interface{}, *float32, *float64,
+		*big.Int, *Decimal, *big.Rat, *string, *time.Time, Scanner:
+		return ConvertValue(dest, v)
+	}
+	return setFieldByKind(f, v)
+}
+
+// setFieldByKind converts through the underlying kind, for named types and
+// sizes ConvertValue does not know.
+func setFieldByKind(f reflect.Value, v interface{}) (err error) {
+	switch f.Kind() {
+	case reflect.Bool:
+		var b bool
+		if err = ConvertValue(&b, v); err == nil {
+			f.SetBool(b)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var i int64
+		if err = ConvertValue(&i, v); err == nil {
+			if f.OverflowInt(i) {
+				return fmt.Errorf("value %d overflows %s", i, f.Type())
+			}
+			f.SetInt(i)
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		var i int64
+		if err = ConvertValue(&i, v); err == nil {
+			if i < 0 || f.OverflowUint(uint64(i)) {
+				return fmt.Errorf("value %d overflows %s", i, f.Type())
+			}
+			f.SetUint(uint64(i))
+		}
+	case reflect.Float32, reflect.Float64:
+		var x float64
+		if err = ConvertValue(&x, v); err == nil {
+			f.SetFloat(x)
+		}
+	case reflect.String:
+		var s string
+		if err = ConvertValue(&s, v); err == nil {
+			f.SetString(s)
+		}
+	default:
+		if f.Kind() == reflect.Slice && f.Type().Elem().Kind() == reflect.Uint8 {
+			var b []byte
+			if err = ConvertValue(&b, v); err == nil {
+				f.SetBytes(b)
+			}
+			return
+		}
+		return fmt.Errorf("unsupported field type %s", f.Type())
+	}
+	return
+}
+
+// ScanStruct copies the current row into the struct pointed to by dest.
+// Columns are matched to fields by their `fb:"name"` tag or field name,
+// ignoring case when there is no exact match, and fields of embedded structs
+// are promoted. Columns without a matching field are skipped.
+func (cursor *Cursor) ScanStruct(dest interface{}) error {
+	if cursor.err != nil {
+		return cursor.err
+	}
+	if cursor.row == nil {
+		return errors.New("fb: ScanStruct called without calling Next")
+	}
+	v := reflect.ValueOf(dest)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return errors.New("fb: ScanStruct destination must be a non-nil pointer to a struct")
+	}
+	return cursor.scanStruct(v.Elem())
+}
+
+func (cursor *Cursor) scanStruct(v reflect.Value) error {
+	plan := structPlanFor(v.Type())
+	for i, col := range cursor.Columns {
+		path := plan.lookup(col.Name)
+		if path == nil {
+			continue
+		}
+		f, err := fieldByPath(v, path)
+		if err == nil {
+			err = setField(f, cursor.row[i])
+		}
+		if err != nil {
+			return fmt.Errorf("fb: ScanStruct error on column %s: %v (%v, %v)", col.Name, err, cursor.row[i], reflect.TypeOf(cursor.row[i]))
+		}
+	}
+	return nil
+}
+
+// QueryStructs appends a struct for each row returned by sql to the slice
+// pointed to by dest, which may hold structs or pointers to structs.
+func (conn *Connection) QueryStructs(dest interface{}, sql string, args ...interface{}) (err error) {
+	slice := reflect.ValueOf(dest)
+	if slice.Kind() != reflect.Ptr || slice.IsNil() || slice.Elem().Kind() != reflect.Slice {
+		return errors.New("fb: QueryStructs destination must be a pointer to a slice")
+	}
+	slice = slice.Elem()
+	elem := slice.Type().Elem()
+	isPtr := elem.Kind() == reflect.Ptr
+	if isPtr {
+		elem = elem.Elem()
+	}
+	if elem.Kind() != reflect.Struct {
+		return errors.New("fb: QueryStructs destination must be a slice of structs")
+	}
+
+	var cursor *Cursor
+	if cursor, err = conn.Execute(sql, args...); err != nil || cursor == nil {
+		return
+	}
+	defer cursor.Close()
+	for cursor.Next() {
+		item := reflect.New(elem)
+		if err = cursor.scanStruct(item.Elem()); err != nil {
+			return
+		}
+		if isPtr {
+			slice.Set(reflect.Append(slice, item))
+		} else {
+			slice.Set(reflect.Append(slice, item.Elem()))
+		}
+	}
+	if cursor.Err() != io.EOF {
+		err = cursor.Err()
+	}
+	return
+}
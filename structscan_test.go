@@ -0,0 +1,134 @@
+package fb
+
+import (
+	"os"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type scanAudit struct {
+	Created time.Time `fb:"CREATED_AT"`
+	Note    string
+}
+
+type scanPerson struct {
+	scanAudit
+	ID       int64 `fb:"ID"`
+	Name     NullableString
+	Nick     *string
+	Age      int
+	Ignored  string `fb:"-"`
+	internal string
+}
+
+type scanStatus string
+
+type scanOrder struct {
+	Status scanStatus
+	Qty    uint16
+	Flags  uint8
+	Level  int8
+	Data   []rune
+}
+
+func TestScanStructKinds(t *testing.T) {
+	st := SuperTest{t}
+	cursor := &Cursor{
+		Columns: []*Column{{Name: "STATUS"}, {Name: "QTY"}, {Name: "FLAGS"}, {Name: "LEVEL"}},
+		row:     Row{"open", int32(300), int16(7), int16(-3)},
+	}
+	var o scanOrder
+	st.Nil(cursor.ScanStruct(&o))
+	st.Equal(scanStatus("open"), o.Status)
+	st.Equal(uint16(300), o.Qty)
+	st.Equal(uint8(7), o.Flags)
+	st.Equal(int8(-3), o.Level)
+
+	cursor.row = Row{"open", int32(-1), int16(7), int16(-3)}
+	st.True(cursor.ScanStruct(&o) != nil)
+	cursor.row = Row{"open", int32(1), int16(256), int16(-3)}
+	st.True(cursor.ScanStruct(&o) != nil)
+
+	cursor.Columns = []*Column{{Name: "DATA"}}
+	cursor.row = Row{"abc"}
+	st.True(cursor.ScanStruct(&o) != nil)
+}
+
+func TestScanStruct(t *testing.T) {
+	st := SuperTest{t}
+	created := time.Date(2020, 1, 2, 3, 4, 5, 0, time.Local)
+	cursor := &Cursor{
+		Columns: []*Column{{Name: "ID"}, {Name: "NAME"}, {Name: "NICK"}, {Name: "age"}, {Name: "CREATED_AT"},
+			{Name: "NOTE"}, {Name: "IGNORED"}, {Name: "EXTRA"}},
+		row: Row{int64(7), nil, "bob", int32(42), created, "n", "x", "y"},
+	}
+
+	var p scanPerson
+	p.Ignored = "keep"
+	nick := "old"
+	p.Nick = &nick
+	st.Nil(cursor.ScanStruct(&p))
+	st.Equal(int64(7), p.ID)
+	st.True(p.Name.Null)
+	st.Equal("bob", *p.Nick)
+	st.Equal("old", nick)
+	st.Equal(42, p.Age)
+	st.Equal(created, p.Created)
+	st.Equal("n", p.Note)
+	st.Equal("keep", p.Ignored)
+	st.Equal("", p.internal)
+
+	cursor.row = Row{int64(8), "Alice", nil, int32(1), created, "", "", ""}
+	st.Nil(cursor.ScanStruct(&p))
+	st.Equal("Alice", p.Name.Value)
+	st.False(p.Name.Null)
+	st.True(p.Nick == nil)
+
+	st.True(cursor.ScanStruct(p) != nil)
+	st.True(cursor.ScanStruct(&cursor.row) != nil)
+
+	plan := structPlanFor(reflect.TypeOf(p))
+	st.True(plan == structPlanFor(reflect.TypeOf(p)))
+}
+
+func TestQueryStructs(t *testing.T) {
+	st := SuperTest{t}
+	const sqlSchema = "CREATE TABLE PEOPLE (ID BIGINT, NAME VARCHAR(20), NICK VARCHAR(20), AGE INT, CREATED_AT TIMESTAMP, NOTE VARCHAR(20))"
+	os.Remove(TestFilename)
+
+	conn, err := Create(TestConnectionString)
+	if err != nil {
+		t.Fatalf("Unexpected error creating database: %s", err)
+	}
+	defer conn.Drop()
+
+	if _, err = conn.Execute(sqlSchema); err != nil {
+		t.Fatalf("Error executing schema: %s", err)
+	}
+	if _, err = conn.Execute("INSERT INTO PEOPLE (ID, NAME, NICK, AGE, NOTE) VALUES (1, 'Ann', NULL, 30, 'a')"); err != nil {
+		t.Fatalf("Error inserting: %s", err)
+	}
+	if _, err = conn.Execute("INSERT INTO PEOPLE (ID, NAME, NICK, AGE, NOTE) VALUES (2, NULL, 'b', 40, 'b')"); err != nil {
+		t.Fatalf("Error inserting: %s", err)
+	}
+
+	var people []scanPerson
+	if err = conn.QueryStructs(&people, "SELECT * FROM PEOPLE ORDER BY ID"); err != nil {
+		t.Fatalf("Unexpected error in QueryStructs: %s", err)
+	}
+	st.MustEqual(2, len(people))
+	st.Equal("Ann", people[0].Name.Value)
+	st.True(people[0].Nick == nil)
+	st.Equal(30, people[0].Age)
+	st.True(people[1].Name.Null)
+	st.Equal("b", *people[1].Nick)
+
+	var ptrs []*scanPerson
+	st.Nil(conn.QueryStructs(&ptrs, "SELECT ID, NOTE FROM PEOPLE WHERE ID = ?", 2))
+	st.MustEqual(1, len(ptrs))
+	st.Equal(int64(2), ptrs[0].ID)
+	st.Equal("b", ptrs[0].Note)
+
+	st.True(conn.QueryStructs(people, "SELECT * FROM PEOPLE") != nil)
+}
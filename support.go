@@ -3,6 +3,8 @@ package fb
 import (
 	"errors"
 	"fmt"
+	"io"
+	"math/big"
 	"regexp"
 	"strconv"
 	"time"
@@ -19,6 +21,33 @@ const (
 
 var reLowercase = regexp.MustCompile("[a-z]")
 
+// reDMLStatement matches statements whose RETURNING row depends on a match,
+// after any leading comments.
+var reDMLStatement = regexp.MustCompile(`(?is)^\s*(?:(?:--[^\n]*\n|/\*.*?\*/)\s*)*(?:INSERT|UPDATE|DELETE|MERGE)\b`)
+
+func bigIntFromIf(v interface{}) (i *big.Int, err error) {
+	switch v := v.(type) {
+	case *big.Int:
+		i = new(big.Int).Set(v)
+	case Decimal:
+		i = v.Rescale(0).unscaled()
+	case *Decimal:
+		i = v.Rescale(0).unscaled()
+	case Interfacer:
+		i, err = bigIntFromIf(v.Interface())
+	default:
+		var s string
+		if s, err = stringFromIf(v); err != nil {
+			return
+		}
+		var ok bool
+		if i, ok = new(big.Int).SetString(s, 10); !ok {
+			return nil, errors.New("integer value expected")
+		}
+	}
+	return
+}
+
 func boolFromIf(v interface{}) (b bool, err error) {
 	var s string
 	s, err = stringFromIf(v)
@@ -34,6 +63,8 @@ func bytesFromIf(v interface{}) (b []byte, err error) {
 		b = v
 	case *[]byte:
 		b = *v
+	case *BlobReader:
+		b, err = io.ReadAll(v)
 	case string:
 		b = []byte(v)
 	case *string:
@@ -72,8 +103,60 @@ func bytesFromIf(v interface{}) (b []byte, err error) {
 	return
 }
 
+func decimalFromIf(v interface{}) (d Decimal, err error) {
+	switch v := v.(type) {
+	case Decimal:
+		d = v
+	case *Decimal:
+		d = *v
+	case *big.Int:
+		d = Decimal{new(big.Int).Set(v), 0}
+	case int64:
+		d = NewDecimal(v, 0)
+	case *int64:
+		d = NewDecimal(*v, 0)
+	case int32:
+		d = NewDecimal(int64(v), 0)
+	case *int32:
+		d = NewDecimal(int64(*v), 0)
+	case int16:
+		d = NewDecimal(int64(v), 0)
+	case *int16:
+		d = NewDecimal(int64(*v), 0)
+	case int:
+		d = NewDecimal(int64(v), 0)
+	case *int:
+		d = NewDecimal(int64(*v), 0)
+	case float64:
+		d, err = ParseDecimal(strconv.FormatFloat(v, 'g', -1, 64))
+	case *float64:
+		d, err = ParseDecimal(strconv.FormatFloat(*v, 'g', -1, 64))
+	case float32:
+		d, err = ParseDecimal(strconv.FormatFloat(float64(v), 'g', -1, 32))
+	case *float32:
+		d, err = ParseDecimal(strconv.FormatFloat(float64(*v), 'g', -1, 32))
+	case string:
+		d, err = ParseDecimal(v)
+	case *string:
+		d, err = ParseDecimal(*v)
+	case Interfacer:
+		d, err = decimalFromIf(v.Interface())
+	case fmt.Stringer:
+		d, err = ParseDecimal(v.String())
+	default:
+		return Decimal{}, errors.New("decimal value expected")
+	}
+	return
+}
+
 func float64FromIf(v interface{}) (f float64, err error) {
 	switch d := v.(type) {
+	case Decimal:
+		f = d.Float64()
+	case *Decimal:
+		f = d.Float64()
+	case *big.Int:
+		f, _ = new(big.Float).SetInt(d).Float64()
 	case float64:
 		f = d
 	case *float64:
@@ -215,12 +298,38 @@ func parseUnknownTime(s string, location *time.Location) (t time.Time, err error
 	return time.Time{}, err
 }
 
+func ratFromIf(v interface{}) (r *big.Rat, err error) {
+	var d Decimal
+	if d, err = decimalFromIf(v); err != nil {
+		return
+	}
+	return d.Rat(), nil
+}
+
+// scaledInt64FromIf returns v as an unscaled integer with scale fractional
+// digits, as stored in NUMERIC and DECIMAL columns.
+func scaledInt64FromIf(v interface{}, scale int) (i int64, err error) {
+	var d Decimal
+	if d, err = decimalFromIf(v); err != nil {
+		return
+	}
+	u := d.Rescale(scale).Unscaled
+	if !u.IsInt64() {
+		return 0, errors.New("decimal overflow")
+	}
+	return u.Int64(), nil
+}
+
 func stringFromIf(v interface{}) (s string, err error) {
 	switch v := v.(type) {
 	case string:
 		s = v
 	case *string:
 		s = *v
+	case *BlobReader:
+		var b []byte
+		b, err = io.ReadAll(v)
+		s = string(b)
 	case int64:
 		s = strconv.FormatInt(v, 10)
 	case *int64:
@@ -304,6 +413,18 @@ func ConvertValue(dest, src interface{}) (err error) {
 		*d, err = float32FromIf(src)
 	case *float64:
 		*d, err = float64FromIf(src)
+	case *big.Int:
+		var i *big.Int
+		if i, err = bigIntFromIf(src); err == nil {
+			d.Set(i)
+		}
+	case *Decimal:
+		*d, err = decimalFromIf(src)
+	case *big.Rat:
+		var r *big.Rat
+		if r, err = ratFromIf(src); err == nil {
+			d.Set(r)
+		}
 	case *string:
 		*d, err = stringFromIf(src)
 	case *time.Time:
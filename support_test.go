@@ -840,3 +840,26 @@ func TestConvertValue_time(t *testing.T) {
 		t.Error("nt2 should be Time zero value")
 	}
 }
+
+func Test_scaledInt64FromIf(t *testing.T) {
+	tests := []struct {
+		v        interface{}
+		scale    int
+		expected int64
+	}{
+		{5.55, 2, 555},
+		{"5.55", 2, 555},
+		{"12345.1234", 4, 123451234},
+		{NewDecimal(12345, 3), 2, 1235},
+		{int32(7), 2, 700},
+		{&NullableString{"0.1", false}, 1, 1},
+	}
+	for _, test := range tests {
+		if v, err := scaledInt64FromIf(test.v, test.scale); err != nil || v != test.expected {
+			t.Errorf("scaledInt64FromIf(%v, %d) failed: got %v, %v", test.v, test.scale, v, err)
+		}
+	}
+	if _, err := scaledInt64FromIf("99999999999999999999", 0); err == nil {
+		t.Error("scaledInt64FromIf should report overflow")
+	}
+}
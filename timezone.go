@@ -0,0 +1,110 @@
+package fb
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Firebird encodes offset zones as offset minutes + tzOneDay; ids above
+// tzOffsetMax refer to named regions listed in RDB$TIME_ZONES.
+const (
+	tzOneDay    = 23*60 + 59
+	tzOffsetMax = 2 * tzOneDay
+)
+
+func offsetZoneName(minutes int) string {
+	sign := '+'
+	if minutes < 0 {
+		sign, minutes = '-', -minutes
+	}
+	return fmt.Sprintf("%c%02d:%02d", sign, minutes/60, minutes%60)
+}
+
+func locationFromOffsetZone(id uint16) *time.Location {
+	minutes := int(id) - tzOneDay
+	return time.FixedZone(offsetZoneName(minutes), minutes*60)
+}
+
+func offsetZoneFromTime(t time.Time) uint16 {
+	_, offset := t.Zone()
+	return uint16(offset/60 + tzOneDay)
+}
+
+// loadTimeZones reads the region names once per connection. It runs while
+// cursor is binding or fetching, so it uses the cursor's transaction rather
+// than starting one of its own.
+func (cursor *Cursor) loadTimeZones() (err error) {
+	conn := cursor.connection
+	if conn.timeZoneNames != nil {
+		return
+	}
+	var zones *Cursor
+	if zones, err = newCursor(conn); err != nil {
+		return
+	}
+	zones.transaction = cursor.transaction
+	defer func() {
+		// a failed execute may leave the statement allocated but not open
+		if zones.open {
+			zones.Close()
+		} else if zones.stmt != 0 {
+			zones.drop()
+		}
+	}()
+	if _, err = zones.execute("SELECT RDB$TIME_ZONE_ID, RDB$TIME_ZONE_NAME FROM RDB$TIME_ZONES"); err != nil {
+		return
+	}
+
+	ids := make(map[string]uint16)
+	names := make(map[uint16]string)
+	for zones.Next() {
+		var id int
+		var name string
+		if err = zones.Scan(&id, &name); err != nil {
+			return
+		}
+		name = strings.TrimSpace(name)
+		ids[name] = uint16(id)
+		names[uint16(id)] = name
+	}
+	if zones.Err() != io.EOF {
+		return zones.Err()
+	}
+	conn.timeZoneIds, conn.timeZoneNames = ids, names
+	return
+}
+
+// locationFromTimeZone maps a Firebird time zone id to a location. When the
+// region is unknown to the Go time zone database, the offset supplied by
+// the extended TZ types is used instead, if there is one.
+func (cursor *Cursor) locationFromTimeZone(id uint16, extOffset int, hasExt bool) (loc *time.Location, err error) {
+	if id <= tzOffsetMax {
+		return locationFromOffsetZone(id), nil
+	}
+	if err = cursor.loadTimeZones(); err != nil {
+		return
+	}
+	name, ok := cursor.connection.timeZoneNames[id]
+	if !ok {
+		return nil, fmt.Errorf("unknown time zone id %d", id)
+	}
+	if loc, err = time.LoadLocation(name); err != nil && hasExt {
+		return time.FixedZone(name, extOffset*60), nil
+	}
+	return
+}
+
+func (cursor *Cursor) timeZoneFromTime(t time.Time) (id uint16, err error) {
+	name := t.Location().String()
+	if name != "Local" && !strings.HasPrefix(name, "+") && !strings.HasPrefix(name, "-") {
+		if err = cursor.loadTimeZones(); err != nil {
+			return
+		}
+		if id, ok := cursor.connection.timeZoneIds[name]; ok {
+			return id, nil
+		}
+	}
+	return offsetZoneFromTime(t), nil
+}
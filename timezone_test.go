@@ -0,0 +1,17 @@
+package fb
+
+import (
+	"testing"
+	"time"
+)
+
+func TestOffsetZones(t *testing.T) {
+	loc := locationFromOffsetZone(tzOneDay + 330)
+	if loc.String() != "+05:30" {
+		t.Errorf("expected +05:30, got %s", loc)
+	}
+	tm := time.Date(2020, 1, 1, 12, 0, 0, 0, time.FixedZone("x", -90*60))
+	if id := offsetZoneFromTime(tm); id != tzOneDay-90 {
+		t.Errorf("expected zone id %d, got %d", tzOneDay-90, id)
+	}
+}
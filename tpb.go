@@ -0,0 +1,131 @@
+package fb
+
+/*
+#include "fb.h"
+*/
+import "C"
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+type Isolation int
+
+const (
+	IsolationConcurrency Isolation = iota
+	IsolationConsistency
+	IsolationReadCommitted
+	IsolationReadCommittedNoRecordVersion
+	// IsolationReadConsistency needs Firebird 4 or later.
+	IsolationReadConsistency
+)
+
+type ReservationMode int
+
+const (
+	ReserveSharedRead ReservationMode = iota
+	ReserveSharedWrite
+	ReserveProtectedRead
+	ReserveProtectedWrite
+)
+
+// TableReservation locks tables when the transaction starts. Table names are
+// passed to the server as given, so unquoted names must be upper case.
+type TableReservation struct {
+	Tables []string
+	Mode   ReservationMode
+}
+
+// TransactionOptions is the structured alternative to the option string of
+// TransactionStart and Begin. The zero value is a read-write concurrency
+// transaction that waits on lock conflicts, like the server default.
+type TransactionOptions struct {
+	Isolation   Isolation
+	ReadOnly    bool
+	NoWait      bool
+	LockTimeout int
+	Reserving   []TableReservation
+	NoAutoUndo  bool
+	AutoCommit  bool
+}
+
+func (opts *TransactionOptions) tpb() ([]byte, error) {
+	var tpb bytes.Buffer
+	tpb.WriteByte(C.isc_tpb_version3)
+
+	if opts.ReadOnly {
+		tpb.WriteByte(C.isc_tpb_read)
+	} else {
+		tpb.WriteByte(C.isc_tpb_write)
+	}
+
+	switch opts.Isolation {
+	case IsolationConcurrency:
+		tpb.WriteByte(C.isc_tpb_concurrency)
+	case IsolationConsistency:
+		tpb.WriteByte(C.isc_tpb_consistency)
+	case IsolationReadCommitted:
+		tpb.Write([]byte{C.isc_tpb_read_committed, C.isc_tpb_rec_version})
+	case IsolationReadCommittedNoRecordVersion:
+		tpb.Write([]byte{C.isc_tpb_read_committed, C.isc_tpb_no_rec_version})
+	case IsolationReadConsistency:
+		tpb.Write([]byte{C.isc_tpb_read_committed, C.isc_tpb_read_consistency})
+	default:
+		return nil, fmt.Errorf("unknown transaction isolation %d", opts.Isolation)
+	}
+
+	if opts.LockTimeout < 0 {
+		return nil, errors.New("negative lock timeout")
+	}
+	if opts.NoWait {
+		if opts.LockTimeout > 0 {
+			return nil, errors.New("lock timeout requires a wait transaction")
+		}
+		tpb.WriteByte(C.isc_tpb_nowait)
+	} else {
+		tpb.WriteByte(C.isc_tpb_wait)
+		if opts.LockTimeout > 0 {
+			tpb.Write([]byte{C.isc_tpb_lock_timeout, 4})
+			binary.Write(&tpb, binary.LittleEndian, uint32(opts.LockTimeout))
+		}
+	}
+
+	for _, r := range opts.Reserving {
+		var lock, share byte
+		switch r.Mode {
+		case ReserveSharedRead:
+			lock, share = C.isc_tpb_lock_read, C.isc_tpb_shared
+		case ReserveSharedWrite:
+			lock, share = C.isc_tpb_lock_write, C.isc_tpb_shared
+		case ReserveProtectedRead:
+			lock, share = C.isc_tpb_lock_read, C.isc_tpb_protected
+		case ReserveProtectedWrite:
+			lock, share = C.isc_tpb_lock_write, C.isc_tpb_protected
+		default:
+			return nil, fmt.Errorf("unknown reservation mode %d", r.Mode)
+		}
+		if len(r.Tables) == 0 {
+			return nil, errors.New("reservation needs a table name list")
+		}
+		for _, table := range r.Tables {
+			if table == "" || len(table) > 255 {
+				return nil, fmt.Errorf("illegal table name %q in reservation", table)
+			}
+			tpb.WriteByte(lock)
+			tpb.WriteByte(byte(len(table)))
+			tpb.WriteString(table)
+			tpb.WriteByte(share)
+		}
+	}
+
+	if opts.NoAutoUndo {
+		tpb.WriteByte(C.isc_tpb_no_auto_undo)
+	}
+	if opts.AutoCommit {
+		tpb.WriteByte(C.isc_tpb_autocommit)
+	}
+	return tpb.Bytes(), nil
+}
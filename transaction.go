@@ -0,0 +1,132 @@
+package fb
+
+/*
+#include <ibase.h>
+*/
+import "C"
+
+import (
+	"io"
+)
+
+type Transaction struct {
+	connection *Connection
+	transact   C.isc_tr_handle
+}
+
+func (conn *Connection) Begin(options interface{}) (tx *Transaction, err error) {
+	if err = conn.check(); err != nil {
+		return
+	}
+	tx = &Transaction{connection: conn}
+	if err = conn.startTransaction(&tx.transact, options); err != nil {
+		return nil, err
+	}
+	if conn.transactions == nil {
+		conn.transactions = make(map[*Transaction]struct{})
+	}
+	conn.transactions[tx] = struct{}{}
+	return tx, nil
+}
+
+// finish forgets a committed or rolled back transaction.
+func (tx *Transaction) finish(err error) error {
+	if err == nil {
+		delete(tx.connection.transactions, tx)
+	}
+	return err
+}
+
+func (tx *Transaction) check() error {
+	if tx.transact == 0 {
+		return &Error{Message: "transaction has already been committed or rolled back"}
+	}
+	return tx.connection.check()
+}
+
+func (tx *Transaction) Commit() (err error) {
+	var isc_status [20]C.ISC_STATUS
+
+	if err = tx.check(); err != nil {
+		return
+	}
+	C.isc_commit_transaction(&isc_status[0], &tx.transact)
+	return tx.finish(fbErrorCheck(&isc_status))
+}
+
+func (tx *Transaction) CommitRetaining() (err error) {
+	var isc_status [20]C.ISC_STATUS
+
+	if err = tx.check(); err != nil {
+		return
+	}
+	C.isc_commit_retaining(&isc_status[0], &tx.transact)
+	return fbErrorCheck(&isc_status)
+}
+
+func (tx *Transaction) Execute(sql string, args ...interface{}) (cursor *Cursor, err error) {
+	if err = tx.check(); err != nil {
+		return
+	}
+	cursor, err = newCursor(tx.connection)
+	if err != nil {
+		return
+	}
+	cursor.transaction = tx
+	rowsAffected, err := cursor.execute(sql, args...)
+	if rowsAffected >= 0 {
+		tx.connection.rowsAffected = rowsAffected
+	}
+	if !cursor.open {
+		cursor = nil
+	}
+	return
+}
+
+func (tx *Transaction) QueryRow(sql string, args ...interface{}) (row Row, err error) {
+	var cursor *Cursor
+	if cursor, err = tx.Execute(sql, args...); err != nil {
+		return
+	}
+	defer cursor.Close()
+	if cursor.Next() {
+		row = cursor.Row()
+	}
+	err = cursor.Err()
+	return
+}
+
+func (tx *Transaction) QueryRows(sql string, args ...interface{}) (rows []Row, err error) {
+	var cursor *Cursor
+	if cursor, err = tx.Execute(sql, args...); err != nil {
+		return
+	}
+	defer cursor.Close()
+	for cursor.Next() {
+		rows = append(rows, cursor.Row())
+	}
+	if cursor.Err() != io.EOF {
+		err = cursor.Err()
+	}
+	return
+}
+
+func (tx *Transaction) Rollback() (err error) {
+	var isc_status [20]C.ISC_STATUS
+
+	if err = tx.check(); err != nil {
+		return
+	}
+	C.isc_rollback_transaction(&isc_status[0], &tx.transact)
+	return tx.finish(fbErrorCheck(&isc_status))
+}
+
+func (tx *Transaction) RollbackRetaining() (err error) {
+	var isc_status [20]C.ISC_STATUS
+
+	if err = tx.check(); err != nil {
+		return
+	}
+	C.isc_rollback_retaining(&isc_status[0], &tx.transact)
+	return fbErrorCheck(&isc_status)
+}
@@ -1,6 +1,7 @@
 package fb
 
 import (
+	"bytes"
 	"os"
 	"strconv"
 	"testing"
@@ -222,3 +223,175 @@ func TestInsertRollback(t *testing.T) {
 		t.Fatal("Expected error due to cursor being at end of data.")
 	}
 }
+
+func TestBeginConcurrentTransactions(t *testing.T) {
+	os.Remove(TestFilename)
+
+	conn, err := Create(TestConnectionString)
+	if err != nil {
+		t.Fatalf("Unexpected error creating database: %s", err)
+	}
+	defer conn.Drop()
+
+	const sqlSchema = "CREATE TABLE TEST (ID INT, NAME VARCHAR(20))"
+	const sqlInsert = "INSERT INTO TEST (ID, NAME) VALUES (?, ?)"
+	const sqlCount = "SELECT COUNT(*) FROM TEST"
+
+	if _, err = conn.Execute(sqlSchema); err != nil {
+		t.Fatalf("Error executing schema: %s", err)
+	}
+
+	report, err := conn.Begin("READ ONLY ISOLATION LEVEL SNAPSHOT")
+	if err != nil {
+		t.Fatalf("Unexpected error starting report transaction: %s", err)
+	}
+	write, err := conn.Begin("")
+	if err != nil {
+		t.Fatalf("Unexpected error starting write transaction: %s", err)
+	}
+	if conn.TransactionStarted() {
+		t.Fatal("Begin should not start the connection's implicit transaction.")
+	}
+	for i := 0; i < 10; i++ {
+		if _, err = write.Execute(sqlInsert, i, strconv.Itoa(i)); err != nil {
+			t.Fatalf("Unexpected error inserting: %s", err)
+		}
+	}
+	if err = write.Commit(); err != nil {
+		t.Fatalf("Unexpected error committing write transaction: %s", err)
+	}
+
+	row, err := report.QueryRow(sqlCount)
+	if err != nil {
+		t.Fatalf("Unexpected error counting in report transaction: %s", err)
+	}
+	if row[0].(int32) != 0 {
+		t.Errorf("Snapshot should not see committed rows, got %v", row[0])
+	}
+	if err = report.Rollback(); err != nil {
+		t.Fatalf("Unexpected error rolling back report transaction: %s", err)
+	}
+
+	row, err = conn.QueryRow(sqlCount)
+	if err != nil {
+		t.Fatalf("Unexpected error counting: %s", err)
+	}
+	if row[0].(int32) != 10 {
+		t.Errorf("Expected 10 rows, got %v", row[0])
+	}
+}
+
+func TestTransactionRetaining(t *testing.T) {
+	os.Remove(TestFilename)
+
+	conn, err := Create(TestConnectionString)
+	if err != nil {
+		t.Fatalf("Unexpected error creating database: %s", err)
+	}
+	defer conn.Drop()
+
+	const sqlSchema = "CREATE TABLE TEST (ID INT, NAME VARCHAR(20))"
+	const sqlInsert = "INSERT INTO TEST (ID, NAME) VALUES (?, ?)"
+	const sqlSelect = "SELECT ID FROM TEST ORDER BY ID"
+
+	if _, err = conn.Execute(sqlSchema); err != nil {
+		t.Fatalf("Error executing schema: %s", err)
+	}
+	tx, err := conn.Begin("")
+	if err != nil {
+		t.Fatalf("Unexpected error starting transaction: %s", err)
+	}
+	if _, err = tx.Execute(sqlInsert, 1, "1"); err != nil {
+		t.Fatalf("Unexpected error inserting: %s", err)
+	}
+	if err = tx.CommitRetaining(); err != nil {
+		t.Fatalf("Unexpected error in CommitRetaining: %s", err)
+	}
+	if _, err = tx.Execute(sqlInsert, 2, "2"); err != nil {
+		t.Fatalf("Unexpected error inserting after CommitRetaining: %s", err)
+	}
+	if err = tx.RollbackRetaining(); err != nil {
+		t.Fatalf("Unexpected error in RollbackRetaining: %s", err)
+	}
+	rows, err := tx.QueryRows(sqlSelect)
+	if err != nil {
+		t.Fatalf("Unexpected error selecting: %s", err)
+	}
+	if len(rows) != 1 {
+		t.Fatalf("Expected 1 row, got %d", len(rows))
+	}
+	if err = tx.Commit(); err != nil {
+		t.Fatalf("Unexpected error committing: %s", err)
+	}
+	if err = tx.Commit(); err == nil {
+		t.Error("Expected error committing a finished transaction.")
+	}
+}
+
+func TestTransactionOptionsTpb(t *testing.T) {
+	st := SuperTest{t}
+
+	tpb, err := (&TransactionOptions{}).tpb()
+	st.Nil(err)
+	st.True(bytes.Equal([]byte{3, 9, 2, 6}, tpb))
+
+	tpb, err = (&TransactionOptions{
+		Isolation:   IsolationReadCommitted,
+		ReadOnly:    true,
+		LockTimeout: 5,
+		Reserving:   []TableReservation{{Tables: []string{"A", "BC"}, Mode: ReserveProtectedWrite}},
+		NoAutoUndo:  true,
+		AutoCommit:  true,
+	}).tpb()
+	st.Nil(err)
+	st.True(bytes.Equal([]byte{3, 8, 15, 17, 6, 21, 4, 5, 0, 0, 0,
+		11, 1, 'A', 4, 11, 2, 'B', 'C', 4, 20, 16}, tpb))
+
+	tpb, err = (&TransactionOptions{Isolation: IsolationConsistency, NoWait: true}).tpb()
+	st.Nil(err)
+	st.True(bytes.Equal([]byte{3, 9, 1, 7}, tpb))
+
+	_, err = (&TransactionOptions{NoWait: true, LockTimeout: 1}).tpb()
+	st.True(err != nil)
+	_, err = (&TransactionOptions{Isolation: Isolation(42)}).tpb()
+	st.True(err != nil)
+	_, err = (&TransactionOptions{Reserving: []TableReservation{{Mode: ReserveSharedRead}}}).tpb()
+	st.True(err != nil)
+}
+
+func TestTransactionStartOptions(t *testing.T) {
+	st := SuperTest{t}
+	os.Remove(TestFilename)
+
+	conn, err := Create(TestConnectionString)
+	if err != nil {
+		t.Fatalf("Unexpected error creating database: %s", err)
+	}
+	defer conn.Drop()
+
+	if _, err = conn.Execute("CREATE TABLE TEST (ID INT)"); err != nil {
+		t.Fatalf("Error executing schema: %s", err)
+	}
+
+	err = conn.TransactionStart(TransactionOptions{ReadOnly: true, Isolation: IsolationReadCommitted})
+	st.Nil(err)
+	_, err = conn.Execute("INSERT INTO TEST (ID) VALUES (1)")
+	st.True(err != nil)
+	st.Nil(conn.Rollback())
+
+	tx, err := conn.Begin(&TransactionOptions{
+		LockTimeout: 1,
+		Reserving:   []TableReservation{{Tables: []string{"TEST"}, Mode: ReserveSharedWrite}},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error starting transaction with options: %s", err)
+	}
+	_, err = tx.Execute("INSERT INTO TEST (ID) VALUES (1)")
+	st.Nil(err)
+	st.Nil(tx.Commit())
+
+	_, err = conn.Begin(TransactionOptions{NoWait: true, LockTimeout: 1})
+	st.True(err != nil)
+	_, err = conn.Begin(42)
+	st.True(err != nil)
+}
@@ -0,0 +1,186 @@
+package fb
+
+/*
+#include <ibase.h>
+*/
+import "C"
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+type User struct {
+	Name       string
+	Password   string
+	FirstName  string
+	MiddleName string
+	LastName   string
+	Admin      bool
+	// SetAdmin makes ModifyUser apply Admin; otherwise the admin right is
+	// left as it is.
+	SetAdmin bool
+	UserID   int
+	GroupID  int
+}
+
+func (u *User) spb(action byte, modify bool) *spb {
+	request := &spb{}
+	request.WriteByte(action)
+	request.addString(C.isc_spb_sec_username, u.Name)
+	if u.Password != "" {
+		request.addString(C.isc_spb_sec_password, u.Password)
+	}
+	if u.FirstName != "" {
+		request.addString(C.isc_spb_sec_firstname, u.FirstName)
+	}
+	if u.MiddleName != "" {
+		request.addString(C.isc_spb_sec_middlename, u.MiddleName)
+	}
+	if u.LastName != "" {
+		request.addString(C.isc_spb_sec_lastname, u.LastName)
+	}
+	if u.UserID != 0 {
+		request.addInt(C.isc_spb_sec_userid, uint32(u.UserID))
+	}
+	if u.GroupID != 0 {
+		request.addInt(C.isc_spb_sec_groupid, uint32(u.GroupID))
+	}
+	if modify && u.SetAdmin || !modify && u.Admin {
+		admin := uint32(0)
+		if u.Admin {
+			admin = 1
+		}
+		request.addInt(C.isc_spb_sec_admin, admin)
+	}
+	return request
+}
+
+func (svc *Service) AddUser(u *User) (err error) {
+	if u.Name == "" || u.Password == "" {
+		return errors.New("user name and password required")
+	}
+	_, err = svc.run("add user", u.spb(C.isc_action_svc_add_user, false), nil)
+	return
+}
+
+// ModifyUser updates the non-empty fields of u, and the admin right when
+// SetAdmin is set.
+func (svc *Service) ModifyUser(u *User) (err error) {
+	if u.Name == "" {
+		return errors.New("user name required")
+	}
+	_, err = svc.run("modify user", u.spb(C.isc_action_svc_modify_user, true), nil)
+	return
+}
+
+func (svc *Service) DeleteUser(name string) (err error) {
+	request := &spb{}
+	request.WriteByte(C.isc_action_svc_delete_user)
+	request.addString(C.isc_spb_sec_username, name)
+	_, err = svc.run("delete user", request, nil)
+	return
+}
+
+func (svc *Service) User(name string) (user *User, err error) {
+	var users []*User
+	if users, err = svc.displayUsers(name); err != nil {
+		return
+	}
+	if len(users) == 0 {
+		return nil, &ServiceError{Action: "display user", Message: "user " + name + " not found"}
+	}
+	return users[0], nil
+}
+
+func (svc *Service) ListUsers() ([]*User, error) {
+	return svc.displayUsers("")
+}
+
+func (svc *Service) displayUsers(name string) (users []*User, err error) {
+	request := &spb{}
+	request.WriteByte(C.isc_action_svc_display_user)
+	if name != "" {
+		request.addString(C.isc_spb_sec_username, name)
+	}
+	if err = svc.start(request); err != nil {
+		return nil, serviceError("display user", err, nil)
+	}
+	// a long list comes in several chunks, which may split a user's items;
+	// an empty chunk ends it
+	var list []byte
+	result := make([]byte, 0xFFFF)
+	for {
+		if err = svc.query([]byte{C.isc_info_svc_get_users}, result); err != nil {
+			return nil, serviceError("display user", err, nil)
+		}
+		if result[0] == C.isc_info_end {
+			break
+		}
+		if result[0] != C.isc_info_svc_get_users {
+			return nil, &ServiceError{Action: "display user", Message: "unexpected service response"}
+		}
+		n := int(binary.LittleEndian.Uint16(result[1:3]))
+		if n == 0 {
+			break
+		}
+		if 3+n > len(result) {
+			return nil, &ServiceError{Action: "display user", Message: "truncated service response"}
+		}
+		list = append(list, result[3:3+n]...)
+	}
+	users, err = parseUsers(list)
+	return
+}
+
+func parseUsers(b []byte) (users []*User, err error) {
+	var user *User
+	for i := 0; i < len(b); {
+		tag := b[i]
+		i++
+		if user == nil && tag != C.isc_spb_sec_username {
+			return nil, errors.New("user list does not start with a user name")
+		}
+		switch tag {
+		case C.isc_spb_sec_username, C.isc_spb_sec_firstname, C.isc_spb_sec_middlename, C.isc_spb_sec_lastname:
+			if i+2 > len(b) {
+				return nil, errors.New("truncated user list")
+			}
+			n := int(binary.LittleEndian.Uint16(b[i:]))
+			i += 2
+			if i+n > len(b) {
+				return nil, errors.New("truncated user list")
+			}
+			s := string(b[i : i+n])
+			i += n
+			switch tag {
+			case C.isc_spb_sec_username:
+				user = &User{Name: s}
+				users = append(users, user)
+			case C.isc_spb_sec_firstname:
+				user.FirstName = s
+			case C.isc_spb_sec_middlename:
+				user.MiddleName = s
+			case C.isc_spb_sec_lastname:
+				user.LastName = s
+			}
+		case C.isc_spb_sec_userid, C.isc_spb_sec_groupid, C.isc_spb_sec_admin:
+			if i+4 > len(b) {
+				return nil, errors.New("truncated user list")
+			}
+			v := int(int32(binary.LittleEndian.Uint32(b[i:])))
+			i += 4
+			switch tag {
+			case C.isc_spb_sec_userid:
+				user.UserID = v
+			case C.isc_spb_sec_groupid:
+				user.GroupID = v
+			case C.isc_spb_sec_admin:
+				user.Admin = v != 0
+			}
+		default:
+			return nil, errors.New("unexpected item in user list")
+		}
+	}
+	return
+}
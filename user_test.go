@@ -0,0 +1,115 @@
+package fb
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestParseUsers(t *testing.T) {
+	st := SuperTest{t}
+	b := []byte{
+		7, 6, 0, 'S', 'Y', 'S', 'D', 'B', 'A',
+		13, 1, 0, 0, 0,
+		7, 5, 0, 'A', 'L', 'I', 'C', 'E',
+		10, 3, 0, 'A', 'n', 'n',
+		12, 3, 0, 'D', 'o', 'e',
+		5, 100, 0, 0, 0,
+		6, 200, 0, 0, 0,
+	}
+	users, err := parseUsers(b)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	st.MustEqual(2, len(users))
+	st.Equal("SYSDBA", users[0].Name)
+	st.True(users[0].Admin)
+	st.Equal("ALICE", users[1].Name)
+	st.Equal("Ann", users[1].FirstName)
+	st.Equal("Doe", users[1].LastName)
+	st.Equal(100, users[1].UserID)
+	st.Equal(200, users[1].GroupID)
+	st.False(users[1].Admin)
+
+	_, err = parseUsers([]byte{10, 3, 0, 'A', 'n', 'n'})
+	st.True(err != nil)
+	_, err = parseUsers([]byte{7, 9, 0, 'A'})
+	st.True(err != nil)
+}
+
+func TestUserSpb(t *testing.T) {
+	st := SuperTest{t}
+	admin := []byte{13, 1, 0, 0, 0}
+	notAdmin := []byte{13, 0, 0, 0, 0}
+	u := &User{Name: "A", Password: "p"}
+	st.False(bytes.Contains(u.spb(1, false).Bytes(), notAdmin))
+	st.False(bytes.Contains(u.spb(1, true).Bytes(), notAdmin))
+	u.Admin = true
+	st.True(bytes.Contains(u.spb(1, false).Bytes(), admin))
+	st.False(bytes.Contains(u.spb(1, true).Bytes(), admin))
+	u.SetAdmin = true
+	st.True(bytes.Contains(u.spb(1, true).Bytes(), admin))
+	u.Admin = false
+	st.True(bytes.Contains(u.spb(1, true).Bytes(), notAdmin))
+}
+
+func TestServiceUsers(t *testing.T) {
+	st := SuperTest{t}
+	svc, err := ConnectService(TestConnectionString)
+	if err != nil {
+		t.Fatalf("Unexpected error attaching to service manager: %s", err)
+	}
+	defer svc.Close()
+
+	svc.DeleteUser("GOTEST_TENANT")
+	user := &User{Name: "GOTEST_TENANT", Password: "secret", FirstName: "Go", LastName: "Tenant"}
+	if err = svc.AddUser(user); err != nil {
+		t.Fatalf("Unexpected error adding user: %s", err)
+	}
+	defer svc.DeleteUser(user.Name)
+
+	found, err := svc.User("GOTEST_TENANT")
+	if err != nil {
+		t.Fatalf("Unexpected error displaying user: %s", err)
+	}
+	st.Equal("Go", found.FirstName)
+	st.Equal("Tenant", found.LastName)
+
+	if err = svc.ModifyUser(&User{Name: "GOTEST_TENANT", MiddleName: "Lang"}); err != nil {
+		t.Fatalf("Unexpected error modifying user: %s", err)
+	}
+	users, err := svc.ListUsers()
+	if err != nil {
+		t.Fatalf("Unexpected error listing users: %s", err)
+	}
+	var listed *User
+	for _, u := range users {
+		if u.Name == "GOTEST_TENANT" {
+			listed = u
+		}
+	}
+	if listed == nil {
+		t.Fatalf("User missing from list")
+	}
+	st.Equal("Lang", listed.MiddleName)
+	st.Equal("Go", listed.FirstName)
+
+	// a partial update leaves the admin right alone
+	if err = svc.ModifyUser(&User{Name: "GOTEST_TENANT", Admin: true, SetAdmin: true}); err != nil {
+		t.Fatalf("Unexpected error modifying user: %s", err)
+	}
+	if err = svc.ModifyUser(&User{Name: "GOTEST_TENANT", Password: "changed"}); err != nil {
+		t.Fatalf("Unexpected error modifying user: %s", err)
+	}
+	found, err = svc.User("GOTEST_TENANT")
+	if err != nil {
+		t.Fatalf("Unexpected error displaying user: %s", err)
+	}
+	st.True(found.Admin)
+
+	if err = svc.DeleteUser("GOTEST_TENANT"); err != nil {
+		t.Fatalf("Unexpected error deleting user: %s", err)
+	}
+	_, err = svc.User("GOTEST_TENANT")
+	st.True(err != nil)
+	st.True(svc.AddUser(&User{Name: "NOPASSWORD"}) != nil)
+}
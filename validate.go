@@ -0,0 +1,152 @@
+package fb
+
+/*
+#include <ibase.h>
+#include "fb.h"
+*/
+import "C"
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+type ValidateOptions struct {
+	Full           bool
+	ReadOnly       bool
+	Mend           bool
+	IgnoreChecksum bool
+	// Online validation needs Firebird 3 or later and does not require
+	// exclusive access; the patterns and lock timeout only apply to it.
+	Online         bool
+	IncludeTables  string
+	ExcludeTables  string
+	IncludeIndexes string
+	ExcludeIndexes string
+	LockTimeout    int
+}
+
+type ValidationIssue struct {
+	Relation   string
+	RelationID int
+	Index      string
+	IndexID    int
+	Errors     int
+	Message    string
+}
+
+type ValidationReport struct {
+	Issues []ValidationIssue
+	Counts map[string]int
+	Lines  []string
+}
+
+func (r *ValidationReport) OK() bool {
+	if len(r.Issues) > 0 {
+		return false
+	}
+	for _, n := range r.Counts {
+		if n > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (svc *Service) Sweep(database string) (err error) {
+	request := &spb{}
+	request.WriteByte(C.isc_action_svc_repair)
+	request.addString(C.isc_spb_dbname, database)
+	request.addInt(C.isc_spb_options, C.isc_spb_rpr_sweep_db)
+	_, err = svc.run("sweep", request, nil)
+	return
+}
+
+func (svc *Service) Validate(database string, opts *ValidateOptions) (report *ValidationReport, err error) {
+	if opts == nil {
+		opts = &ValidateOptions{}
+	}
+	request := &spb{}
+	if opts.Online {
+		request.WriteByte(C.isc_action_svc_validate)
+		request.addString(C.isc_spb_dbname, database)
+		if opts.IncludeTables != "" {
+			request.addString(C.isc_spb_val_tab_incl, opts.IncludeTables)
+		}
+		if opts.ExcludeTables != "" {
+			request.addString(C.isc_spb_val_tab_excl, opts.ExcludeTables)
+		}
+		if opts.IncludeIndexes != "" {
+			request.addString(C.isc_spb_val_idx_incl, opts.IncludeIndexes)
+		}
+		if opts.ExcludeIndexes != "" {
+			request.addString(C.isc_spb_val_idx_excl, opts.ExcludeIndexes)
+		}
+		if opts.LockTimeout != 0 {
+			request.addInt(C.isc_spb_val_lock_timeout, uint32(opts.LockTimeout))
+		}
+	} else {
+		flags := uint32(C.isc_spb_rpr_validate_db)
+		if opts.Full {
+			flags |= C.isc_spb_rpr_full
+		}
+		if opts.ReadOnly {
+			flags |= C.isc_spb_rpr_check_db
+		}
+		if opts.Mend {
+			flags |= C.isc_spb_rpr_mend_db
+		}
+		if opts.IgnoreChecksum {
+			flags |= C.isc_spb_rpr_ignore_checksum
+		}
+		request.WriteByte(C.isc_action_svc_repair)
+		request.addString(C.isc_spb_dbname, database)
+		request.addInt(C.isc_spb_options, flags)
+	}
+	var lines []string
+	if lines, err = svc.run("validate", request, nil); err != nil {
+		return
+	}
+	return parseValidation(lines), nil
+}
+
+var (
+	reValidationTime     = regexp.MustCompile(`^\d\d:\d\d:\d\d\.\d+\s+`)
+	reValidationRelation = regexp.MustCompile(`^Relation (\d+) \(([^)]*)\)`)
+	reValidationIndex    = regexp.MustCompile(`^Index (\d+) \(([^)]*)\)`)
+	reValidationErrors   = regexp.MustCompile(`: (\d+) ERRORS found`)
+	reValidationCount    = regexp.MustCompile(`^Number of (.+?)\s*:\s*(\d+)$`)
+)
+
+func parseValidation(lines []string) *ValidationReport {
+	report := &ValidationReport{Counts: make(map[string]int), Lines: lines}
+	var relation string
+	var relationID int
+	for _, line := range lines {
+		line = strings.TrimSpace(reValidationTime.ReplaceAllString(strings.TrimSpace(line), ""))
+		if m := reValidationCount.FindStringSubmatch(line); m != nil {
+			report.Counts[m[1]], _ = strconv.Atoi(m[2])
+			continue
+		}
+		if strings.HasPrefix(line, "Summary of validation errors") {
+			continue
+		}
+		issue := ValidationIssue{Relation: relation, RelationID: relationID, Message: line}
+		if m := reValidationRelation.FindStringSubmatch(line); m != nil {
+			relationID, _ = strconv.Atoi(m[1])
+			relation = m[2]
+			issue.Relation, issue.RelationID = relation, relationID
+		} else if m := reValidationIndex.FindStringSubmatch(line); m != nil {
+			issue.IndexID, _ = strconv.Atoi(m[1])
+			issue.Index = m[2]
+		}
+		if m := reValidationErrors.FindStringSubmatch(line); m != nil {
+			issue.Errors, _ = strconv.Atoi(m[1])
+			report.Issues = append(report.Issues, issue)
+		} else if lower := strings.ToLower(line); strings.Contains(lower, "error") || strings.Contains(lower, "corrupt") {
+			report.Issues = append(report.Issues, issue)
+		}
+	}
+	return report
+}
@@ -0,0 +1,79 @@
+package fb
+
+import (
+	"os"
+	"testing"
+)
+
+func TestParseValidation(t *testing.T) {
+	st := SuperTest{t}
+
+	online := []string{
+		"21:43:12.94 Validation started",
+		"21:43:12.95 Relation 128 (TEST)",
+		"21:43:12.95   process pointer page    0 of    1",
+		"21:43:12.95 Index 1 (RDB$PRIMARY1)",
+		"21:43:12.95 Relation 128 (TEST) is ok",
+		"21:43:12.95 Relation 129 (BROKEN)",
+		"21:43:12.95 Index 2 (IDX_BROKEN) : 1 ERRORS found",
+		"21:43:12.96 Relation 129 (BROKEN) : 3 ERRORS found",
+		"21:43:12.96 Validation finished",
+	}
+	report := parseValidation(online)
+	st.False(report.OK())
+	st.MustEqual(2, len(report.Issues))
+	st.Equal("BROKEN", report.Issues[0].Relation)
+	st.Equal("IDX_BROKEN", report.Issues[0].Index)
+	st.Equal(2, report.Issues[0].IndexID)
+	st.Equal(1, report.Issues[0].Errors)
+	st.Equal(129, report.Issues[1].RelationID)
+	st.Equal(3, report.Issues[1].Errors)
+
+	offline := []string{
+		"Summary of validation errors",
+		"\tNumber of record level errors\t: 2",
+		"\tNumber of data page errors\t: 0",
+	}
+	report = parseValidation(offline)
+	st.False(report.OK())
+	st.Equal(0, len(report.Issues))
+	st.Equal(2, report.Counts["record level errors"])
+	st.Equal(0, report.Counts["data page errors"])
+
+	st.True(parseValidation([]string{"Validation started", "Relation 128 (TEST) is ok"}).OK())
+}
+
+func TestServiceSweepValidate(t *testing.T) {
+	st := SuperTest{t}
+	os.Remove(TestFilename)
+
+	conn, err := Create(TestConnectionString)
+	if err != nil {
+		t.Fatalf("Unexpected error creating database: %s", err)
+	}
+	if _, err = conn.Execute("CREATE TABLE TEST (ID INTEGER NOT NULL PRIMARY KEY)"); err != nil {
+		t.Fatalf("Error creating table: %s", err)
+	}
+	conn.Close()
+	defer Drop(TestConnectionString)
+
+	svc, err := ConnectService(TestConnectionString)
+	if err != nil {
+		t.Fatalf("Unexpected error attaching to service manager: %s", err)
+	}
+	defer svc.Close()
+
+	st.Nil(svc.Sweep(TestFilename))
+
+	report, err := svc.Validate(TestFilename, &ValidateOptions{Full: true, ReadOnly: true})
+	if err != nil {
+		t.Fatalf("Unexpected error in validate: %s", err)
+	}
+	st.True(report.OK())
+
+	report, err = svc.Validate(TestFilename, &ValidateOptions{Online: true, IncludeTables: "TEST"})
+	if err != nil {
+		t.Fatalf("Unexpected error in online validate: %s", err)
+	}
+	st.True(report.OK())
+}